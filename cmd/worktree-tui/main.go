@@ -1,13 +1,40 @@
 package main
 
 import (
+	"flag"
+	"fmt"
 	"log"
+	"os"
 
+	"github.com/fredrikmwold/git-worktree-tui/internal/cli"
+	"github.com/fredrikmwold/git-worktree-tui/internal/config"
+	"github.com/fredrikmwold/git-worktree-tui/internal/theme"
 	"github.com/fredrikmwold/git-worktree-tui/internal/tui"
 )
 
 func main() {
-	p := tui.NewProgram()
+	var opts tui.Options
+	flag.StringVar(&opts.CdFile, "cd-file", "", "on Enter, write the selected worktree's path to this file and quit")
+	flag.Usage = func() { fmt.Fprint(flag.CommandLine.Output(), cli.Usage) }
+	flag.Parse()
+
+	cfg, err := config.Load()
+	if err != nil {
+		// help and init don't read settings, so a broken config file can't block them
+		if flag.NArg() == 0 || cli.NeedsConfig(flag.Arg(0)) {
+			log.Fatal(err)
+		}
+		cfg = config.Default()
+	}
+	// Subcommands run non-interactively; no arguments launches the TUI
+	if flag.NArg() > 0 {
+		os.Exit(cli.Run(cfg, flag.Args(), os.Stdout, os.Stderr))
+	}
+	// The TUI styles read the palette when built, so pick it first
+	if err := theme.Use(cfg.Theme, cfg.Themes); err != nil {
+		log.Fatal(err)
+	}
+	p := tui.NewProgram(cfg, opts)
 	if err := p.Start(); err != nil {
 		log.Fatal(err)
 	}
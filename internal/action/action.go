@@ -0,0 +1,229 @@
+// Package action defines the ways a worktree can be opened: the editor, a tmux
+// window, a terminal, the file manager, or user-defined command templates.
+package action
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"text/template"
+
+	"github.com/fredrikmwold/git-worktree-tui/internal/editor"
+	"github.com/fredrikmwold/git-worktree-tui/internal/mux"
+	"github.com/fredrikmwold/git-worktree-tui/internal/shellwords"
+)
+
+// Mode controls what the TUI does while and after an action runs.
+type Mode string
+
+const (
+	// ModeQuit runs the command in the terminal and exits when it finishes.
+	ModeQuit Mode = "quit"
+	// ModeStay runs the command in the terminal and returns to the list afterwards.
+	ModeStay Mode = "stay"
+	// ModeBackground starts the command detached and keeps the TUI running.
+	ModeBackground Mode = "background"
+)
+
+// ParseMode validates a mode name from configuration.
+func ParseMode(s string) (Mode, error) {
+	switch m := Mode(s); m {
+	case ModeQuit, ModeStay, ModeBackground:
+		return m, nil
+	}
+	return "", fmt.Errorf("invalid mode %q (want quit, stay or background)", s)
+}
+
+// Vars are the values available to command templates.
+type Vars struct {
+	Path   string // absolute worktree path
+	Branch string // branch name; empty when detached
+	Name   string // worktree folder name
+	Repo   string // repository folder name
+}
+
+// Action is a named way of opening a worktree.
+type Action struct {
+	Name        string
+	Description string
+	// Command is split into shell words and each word is rendered as a
+	// text/template with Vars, so paths with spaces stay single arguments.
+	// Use {{quote .Path}} when building a string for `sh -c`.
+	Command string
+	Mode    Mode
+	// build replaces Command for built-ins that need more than a template.
+	build func(Vars) (*exec.Cmd, error)
+}
+
+var funcs = template.FuncMap{"quote": shellwords.Quote}
+
+// New returns a user-defined action, validating its command template.
+func New(name, command string, mode Mode) (Action, error) {
+	a := Action{Name: name, Command: command, Mode: mode, Description: command}
+	if command == "" {
+		return a, fmt.Errorf("command required")
+	}
+	words, err := shellwords.Split(command)
+	if err != nil {
+		return a, err
+	}
+	for _, w := range words {
+		if _, err := template.New(name).Funcs(funcs).Parse(w); err != nil {
+			return a, err
+		}
+	}
+	return a, nil
+}
+
+// Cmd builds the command for v. The working directory is the worktree, and
+// the command is attached to the terminal unless it runs in the background.
+func (a Action) Cmd(v Vars) (*exec.Cmd, error) {
+	var cmd *exec.Cmd
+	if a.build != nil {
+		c, err := a.build(v)
+		if err != nil {
+			return nil, err
+		}
+		cmd = c
+	} else {
+		words, err := shellwords.Split(a.Command)
+		if err != nil {
+			return nil, err
+		}
+		args := make([]string, 0, len(words))
+		for _, w := range words {
+			t, err := template.New(a.Name).Funcs(funcs).Option("missingkey=error").Parse(w)
+			if err != nil {
+				return nil, err
+			}
+			var b bytes.Buffer
+			if err := t.Execute(&b, v); err != nil {
+				return nil, err
+			}
+			args = append(args, b.String())
+		}
+		if len(args) == 0 {
+			return nil, fmt.Errorf("action %s: empty command", a.Name)
+		}
+		cmd = exec.Command(args[0], args[1:]...)
+	}
+	if cmd.Dir == "" {
+		cmd.Dir = v.Path
+	}
+	if a.Mode == ModeBackground {
+		cmd.Stdin, cmd.Stdout, cmd.Stderr = nil, nil, nil
+		detach(cmd)
+	} else {
+		cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	}
+	return cmd, nil
+}
+
+// Start launches a background action and reaps it when it exits.
+func Start(cmd *exec.Cmd) error {
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go cmd.Wait()
+	return nil
+}
+
+// Builtins returns the actions available without configuration. Actions that
+// depend on the environment (tmux, $TERMINAL, $SHELL) are only included when usable.
+// editorCmd is the configured editor command template, or "" for $VISUAL/$EDITOR.
+func Builtins(editorCmd string) []Action {
+	desc := "Open in $VISUAL/$EDITOR"
+	if editorCmd != "" {
+		desc = "Open with " + editorCmd
+	}
+	acts := []Action{{
+		Name:        "editor",
+		Description: desc,
+		Mode:        ModeQuit,
+		build:       func(v Vars) (*exec.Cmd, error) { return editor.Command(editorCmd, v.Path) },
+	}}
+	if os.Getenv("TMUX") != "" {
+		acts = append(acts, Action{
+			Name:        "tmux-window",
+			Description: "Open a new tmux window in the worktree",
+			Command:     "tmux new-window -c {{.Path}} -n {{.Name}}",
+			Mode:        ModeBackground,
+		})
+	}
+	for _, k := range mux.Kinds {
+		if !mux.Available(k) {
+			continue
+		}
+		acts = append(acts, Action{
+			Name:        string(k) + "-session",
+			Description: fmt.Sprintf("Create or attach to the worktree's %s session", k),
+			Mode:        ModeStay,
+			build:       func(v Vars) (*exec.Cmd, error) { return mux.OpenCmd(k, v.Path) },
+		})
+	}
+	if term := os.Getenv("TERMINAL"); term != "" {
+		acts = append(acts, Action{
+			Name:        "terminal",
+			Description: "Open $TERMINAL in the worktree",
+			Mode:        ModeBackground,
+			build:       func(Vars) (*exec.Cmd, error) { return exec.Command(term), nil },
+		})
+	}
+	acts = append(acts, Action{
+		Name:        "file-manager",
+		Description: "Show the worktree in the file manager",
+		Command:     fileManagerCommand(),
+		Mode:        ModeBackground,
+	})
+	if sh := os.Getenv("SHELL"); sh != "" {
+		acts = append(acts, Action{
+			Name:        "shell",
+			Description: "Start $SHELL in the worktree; exit it to come back",
+			Mode:        ModeStay,
+			build:       func(Vars) (*exec.Cmd, error) { return exec.Command(sh), nil },
+		})
+	}
+	return acts
+}
+
+func fileManagerCommand() string {
+	switch runtime.GOOS {
+	case "darwin":
+		return "open {{.Path}}"
+	case "windows":
+		return "explorer {{.Path}}"
+	}
+	return "xdg-open {{.Path}}"
+}
+
+// Merge returns base with user actions appended; a user action with the same
+// name as a built-in replaces it in place.
+func Merge(base, user []Action) []Action {
+	out := append([]Action(nil), base...)
+	for _, u := range user {
+		replaced := false
+		for i := range out {
+			if out[i].Name == u.Name {
+				out[i] = u
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			out = append(out, u)
+		}
+	}
+	return out
+}
+
+// Find returns the action with the given name.
+func Find(acts []Action, name string) (Action, bool) {
+	for _, a := range acts {
+		if a.Name == name {
+			return a, true
+		}
+	}
+	return Action{}, false
+}
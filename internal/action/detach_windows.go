@@ -0,0 +1,13 @@
+//go:build windows
+
+package action
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// detach starts cmd in its own process group so it outlives the TUI.
+func detach(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP}
+}
@@ -0,0 +1,107 @@
+// Package branchname turns free text into branch names that follow a team's
+// conventions: a prefix such as "feat/", a ticket key and a slug of the title,
+// put together by a text/template and checked against an optional policy.
+package branchname
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/fredrikmwold/git-worktree-tui/internal/git"
+)
+
+// DefaultTemplate is used for titles that aren't branch names already,
+// e.g. "ABC-123 Fix login" with prefix "fix/" becomes "fix/ABC-123-fix-login".
+const DefaultTemplate = "{{.Prefix}}{{with .Ticket}}{{.}}-{{end}}{{.Slug}}"
+
+// Vars are the variables available to branch name templates.
+type Vars struct {
+	Prefix string // chosen prefix, e.g. "feat/"; empty for none
+	Title  string // text as typed, trimmed
+	Ticket string // ticket key the title starts with, e.g. "ABC-123"; empty if none
+	Slug   string // rest of the title as lower-case words joined by '-', e.g. "fix-login"
+}
+
+var ticketKey = regexp.MustCompile(`^([A-Z][A-Z0-9]*-[0-9]+)\b`)
+
+// NewVars splits title into its ticket key and slug.
+func NewVars(prefix, title string) Vars {
+	title = strings.TrimSpace(title)
+	v := Vars{Prefix: prefix, Title: title}
+	rest := title
+	if m := ticketKey.FindStringSubmatch(title); m != nil {
+		v.Ticket = m[1]
+		rest = title[len(m[0]):]
+	}
+	v.Slug = Slugify(rest)
+	return v
+}
+
+// Slugify lower-cases s and joins its words (runs of letters and digits) with '-',
+// e.g. "Fix login on Safari!" -> "fix-login-on-safari".
+func Slugify(s string) string {
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(words, "-")
+}
+
+// Namer builds branch names from a template and checks them against a policy.
+// The zero value keeps valid branch names as typed and slugifies anything else
+// with DefaultTemplate.
+type Namer struct {
+	tmpl    *template.Template
+	policy  *regexp.Regexp
+	pattern string
+}
+
+// New returns a Namer for tmpl (empty for the default behavior) and pattern,
+// a regular expression every new branch name must match in full (empty for none).
+func New(tmpl, pattern string) (Namer, error) {
+	var n Namer
+	if tmpl != "" {
+		t, err := template.New("branch").Option("missingkey=error").Parse(tmpl)
+		if err != nil {
+			return n, fmt.Errorf("branch template: %w", err)
+		}
+		n.tmpl = t
+	}
+	if pattern != "" {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return n, fmt.Errorf("branch pattern: %w", err)
+		}
+		n.policy, n.pattern = regexp.MustCompile(`^(?:`+pattern+`)$`), pattern
+	}
+	return n, nil
+}
+
+// Name returns the branch name for title with prefix.
+func (n Namer) Name(prefix, title string) (string, error) {
+	title = strings.TrimSpace(title)
+	t := n.tmpl
+	if t == nil {
+		if git.ValidateBranchName(title) == nil {
+			return prefix + title, nil
+		}
+		t = defaultTemplate
+	}
+	var b bytes.Buffer
+	if err := t.Execute(&b, NewVars(prefix, title)); err != nil {
+		return "", fmt.Errorf("branch template: %w", err)
+	}
+	return strings.TrimSpace(b.String()), nil
+}
+
+var defaultTemplate = template.Must(template.New("branch").Parse(DefaultTemplate))
+
+// Check returns an error when name doesn't follow the naming policy.
+func (n Namer) Check(name string) error {
+	if n.policy != nil && !n.policy.MatchString(name) {
+		return fmt.Errorf("%s doesn't match the branch naming policy %s", name, n.pattern)
+	}
+	return nil
+}
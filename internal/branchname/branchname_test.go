@@ -0,0 +1,132 @@
+package branchname
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewVars(t *testing.T) {
+	tests := []struct {
+		prefix, title string
+		want          Vars
+	}{
+		{"", "Fix login", Vars{Title: "Fix login", Slug: "fix-login"}},
+		{"fix/", "  ABC-123 Fix login on Safari! ", Vars{Prefix: "fix/", Title: "ABC-123 Fix login on Safari!", Ticket: "ABC-123", Slug: "fix-login-on-safari"}},
+		{"", "ABC-123", Vars{Title: "ABC-123", Ticket: "ABC-123"}},
+		{"", "abc-123 fix", Vars{Title: "abc-123 fix", Slug: "abc-123-fix"}},
+		{"", "ABC-123x fix", Vars{Title: "ABC-123x fix", Slug: "abc-123x-fix"}},
+		{"", "Fix ABC-123", Vars{Title: "Fix ABC-123", Slug: "fix-abc-123"}},
+	}
+	for _, tt := range tests {
+		if got := NewVars(tt.prefix, tt.title); got != tt.want {
+			t.Errorf("NewVars(%q, %q) = %+v, want %+v", tt.prefix, tt.title, got, tt.want)
+		}
+	}
+}
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"", ""},
+		{"Fix login on Safari!", "fix-login-on-safari"},
+		{"  --already-slugged--  ", "already-slugged"},
+		{"feature/x_y.z", "feature-x-y-z"},
+		{"Ünïcode Wörds", "ünïcode-wörds"},
+		{"v2 API", "v2-api"},
+	}
+	for _, tt := range tests {
+		if got := Slugify(tt.in); got != tt.want {
+			t.Errorf("Slugify(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		tmpl, pattern string
+		wantErr       string
+	}{
+		{tmpl: "", pattern: ""},
+		{tmpl: DefaultTemplate, pattern: "(feat|fix)/.+"},
+		{tmpl: "{{.Prefix", wantErr: "branch template"},
+		{pattern: "(feat", wantErr: "branch pattern: error parsing regexp: missing closing ): `(feat`"},
+	}
+	for _, tt := range tests {
+		_, err := New(tt.tmpl, tt.pattern)
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("New(%q, %q) error = %v", tt.tmpl, tt.pattern, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("New(%q, %q) error = %v, want one containing %q", tt.tmpl, tt.pattern, err, tt.wantErr)
+		}
+	}
+}
+
+func TestNamerName(t *testing.T) {
+	tests := []struct {
+		tmpl, prefix, title string
+		want                string
+		wantErr             bool
+	}{
+		// Without a template, valid names are kept and free text is slugified
+		{prefix: "", title: "feature/x", want: "feature/x"},
+		{prefix: "fix/", title: "login", want: "fix/login"},
+		{prefix: "", title: "  main ", want: "main"},
+		{prefix: "", title: "Fix login!", want: "fix-login"},
+		{prefix: "fix/", title: "ABC-123 Fix login on Safari", want: "fix/ABC-123-fix-login-on-safari"},
+		// A template applies to every name
+		{tmpl: "{{.Prefix}}{{.Ticket}}-{{.Slug}}", prefix: "feat/", title: "ABC-1 Add export", want: "feat/ABC-1-add-export"},
+		{tmpl: "{{.Prefix}}{{.Slug}}", prefix: "", title: "feature/x", want: "feature-x"},
+		{tmpl: "me/{{.Title}}", title: "as typed", want: "me/as typed"},
+		{tmpl: "{{.Nope}}", title: "x", wantErr: true},
+	}
+	for _, tt := range tests {
+		n, err := New(tt.tmpl, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := n.Name(tt.prefix, tt.title)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Name(%q, %q) with template %q error = %v, wantErr %v", tt.prefix, tt.title, tt.tmpl, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Name(%q, %q) with template %q = %q, want %q", tt.prefix, tt.title, tt.tmpl, got, tt.want)
+		}
+	}
+}
+
+func TestNamerCheck(t *testing.T) {
+	tests := []struct {
+		pattern, name string
+		ok            bool
+	}{
+		{"", "anything goes", true},
+		{"(feat|fix)/.+", "feat/x", true},
+		{"(feat|fix)/.+", "fix/ABC-1-x", true},
+		{"(feat|fix)/.+", "feature/x", false},
+		{"(feat|fix)/.+", "my/feat/x", false},
+		{"(feat|fix)/.+", "feat/", false},
+		// The pattern must match in full, even with a top-level alternation
+		{"feat|fix", "fix", true},
+		{"feat|fix", "fixture", false},
+		{"feat|fix", "prefeat", false},
+	}
+	for _, tt := range tests {
+		n, err := New("", tt.pattern)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = n.Check(tt.name)
+		if (err == nil) != tt.ok {
+			t.Errorf("Check(%q) with pattern %q error = %v, want ok %v", tt.name, tt.pattern, err, tt.ok)
+		}
+		if err != nil && !strings.Contains(err.Error(), tt.pattern) {
+			t.Errorf("Check(%q) error %q doesn't name the pattern %q", tt.name, err, tt.pattern)
+		}
+	}
+}
@@ -0,0 +1,489 @@
+// Package cli implements the non-interactive subcommands (list, add, remove, open)
+// that share the git helpers and naming conventions of the TUI.
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/fredrikmwold/git-worktree-tui/internal/branchname"
+	"github.com/fredrikmwold/git-worktree-tui/internal/config"
+	"github.com/fredrikmwold/git-worktree-tui/internal/editor"
+	"github.com/fredrikmwold/git-worktree-tui/internal/git"
+	"github.com/fredrikmwold/git-worktree-tui/internal/hook"
+	"github.com/fredrikmwold/git-worktree-tui/internal/mux"
+	"github.com/fredrikmwold/git-worktree-tui/internal/seed"
+)
+
+// Usage describes the command line.
+const Usage = `Usage:
+  worktree-tui [--cd-file <file>]      Launch the interactive TUI
+  worktree-tui list [--json] [--status]
+  worktree-tui add <branch> [--from <ref>] [--path <path>] [--no-hooks] [--json]
+  worktree-tui remove <name|path|branch> [--force] [--delete-branch] [--force-branch]
+  worktree-tui open <name|path|branch>
+  worktree-tui init <bash|zsh|fish> [--cmd <name>]
+  worktree-tui config                  Show config files and effective settings
+
+Worktrees can be referred to by folder name, path or branch.
+
+With --cd-file, selecting a worktree writes its path to <file> and quits
+instead of opening an editor. "init" prints a shell function that uses this
+to cd into the selected worktree, e.g. add to ~/.bashrc:
+
+  eval "$(worktree-tui init bash)"
+`
+
+// NeedsConfig reports whether the subcommand cmd uses the user's settings;
+// the others also run when the config can't be loaded.
+func NeedsConfig(cmd string) bool {
+	switch cmd {
+	case "help", "-h", "--help", "init":
+		return false
+	}
+	return true
+}
+
+// Run executes the subcommand in args and returns the process exit code.
+func Run(cfg config.Config, args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, Usage)
+		return 2
+	}
+	c := &runner{cfg: cfg, stdout: stdout, stderr: stderr}
+	if note := cfg.UntrustedNote(); note != "" && (args[0] == "add" || args[0] == "open") {
+		fmt.Fprintln(stderr, "worktree-tui: "+note)
+	}
+	var err error
+	switch args[0] {
+	case "list":
+		err = c.list(args[1:])
+	case "add":
+		err = c.add(args[1:])
+	case "remove":
+		err = c.remove(args[1:])
+	case "open":
+		err = c.open(args[1:])
+	case "init":
+		err = c.init(args[1:])
+	case "config":
+		err = c.config(args[1:])
+	case "help", "-h", "--help":
+		fmt.Fprint(stdout, Usage)
+		return 0
+	default:
+		fmt.Fprintf(stderr, "unknown command %q\n\n%s", args[0], Usage)
+		return 2
+	}
+	var ee *exitError
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, flag.ErrHelp):
+		return 0
+	case errors.As(err, &ee):
+		return ee.code
+	}
+	fmt.Fprintf(stderr, "worktree-tui %s: %v\n", args[0], err)
+	return 1
+}
+
+// exitError carries a child process exit code through Run without extra output.
+type exitError struct{ code int }
+
+func (e *exitError) Error() string { return fmt.Sprintf("exit status %d", e.code) }
+
+type runner struct {
+	cfg    config.Config
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// newFlags returns a flag set that reports errors to stderr instead of exiting.
+func (c *runner) newFlags(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	return fs
+}
+
+// parseArgs parses flags and positional arguments in any order, so both
+// `add --from main feat/x` and `add feat/x --from main` work.
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var pos []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			return pos, nil
+		}
+		pos = append(pos, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+// worktreeJSON is the --json representation of a worktree.
+type worktreeJSON struct {
+	Name        string      `json:"name"`
+	Path        string      `json:"path"`
+	Branch      string      `json:"branch,omitempty"`
+	HEAD        string      `json:"head,omitempty"`
+	Main        bool        `json:"main"`
+	Bare        bool        `json:"bare"`
+	Detached    bool        `json:"detached"`
+	Locked      bool        `json:"locked"`
+	LockReason  string      `json:"lockReason,omitempty"`
+	Prunable    bool        `json:"prunable"`
+	PruneReason string      `json:"pruneReason,omitempty"`
+	Status      *statusJSON `json:"status,omitempty"`
+}
+
+type statusJSON struct {
+	Staged     int    `json:"staged"`
+	Unstaged   int    `json:"unstaged"`
+	Untracked  int    `json:"untracked"`
+	Conflicted int    `json:"conflicted"`
+	Upstream   string `json:"upstream,omitempty"`
+	Ahead      int    `json:"ahead"`
+	Behind     int    `json:"behind"`
+}
+
+func toJSON(wt git.Worktree) worktreeJSON {
+	return worktreeJSON{
+		Name:        filepath.Base(wt.Path),
+		Path:        wt.Path,
+		Branch:      wt.BranchName(),
+		HEAD:        wt.HEAD,
+		Main:        wt.IsMain,
+		Bare:        wt.IsBare,
+		Detached:    wt.IsDetached,
+		Locked:      wt.Locked,
+		LockReason:  wt.LockReason,
+		Prunable:    wt.Prunable,
+		PruneReason: wt.PruneReason,
+	}
+}
+
+func (c *runner) writeJSON(v any) error {
+	enc := json.NewEncoder(c.stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func (c *runner) list(args []string) error {
+	fs := c.newFlags("list")
+	asJSON := fs.Bool("json", false, "print JSON")
+	withStatus := fs.Bool("status", false, "include uncommitted changes and ahead/behind counts")
+	pos, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) > 0 {
+		return fmt.Errorf("unexpected argument %q", pos[0])
+	}
+	wts, err := git.ListWorktrees()
+	if err != nil {
+		return err
+	}
+	out := make([]worktreeJSON, 0, len(wts))
+	for _, wt := range wts {
+		j := toJSON(wt)
+		if *withStatus && !wt.IsBare && !wt.Prunable {
+			if st, err := git.WorktreeStatus(wt.Path); err == nil {
+				j.Status = &statusJSON{st.Staged, st.Unstaged, st.Untracked, st.Conflicted, st.Upstream, st.Ahead, st.Behind}
+			}
+		}
+		out = append(out, j)
+	}
+	if *asJSON {
+		return c.writeJSON(out)
+	}
+	tw := tabwriter.NewWriter(c.stdout, 0, 4, 2, ' ', 0)
+	for _, j := range out {
+		branch := j.Branch
+		if branch == "" && j.HEAD != "" {
+			branch = git.ShortHash(j.HEAD)
+		}
+		cols := []string{j.Name, branch, j.Path}
+		if *withStatus {
+			cols = append(cols, statusText(j.Status))
+		}
+		cols = append(cols, flagsText(j))
+		fmt.Fprintln(tw, strings.Join(cols, "\t"))
+	}
+	return tw.Flush()
+}
+
+// flagsText lists a worktree's state markers, e.g. "main,locked".
+func flagsText(j worktreeJSON) string {
+	var fl []string
+	for _, f := range []struct {
+		on   bool
+		name string
+	}{{j.Main, "main"}, {j.Bare, "bare"}, {j.Detached, "detached"}, {j.Locked, "locked"}, {j.Prunable, "prunable"}} {
+		if f.on {
+			fl = append(fl, f.name)
+		}
+	}
+	return strings.Join(fl, ",")
+}
+
+// statusText renders status counts like the TUI, e.g. "+1 ~2 ?3 ↑1 ↓0".
+func statusText(st *statusJSON) string {
+	if st == nil {
+		return "-"
+	}
+	var parts []string
+	for _, p := range []struct {
+		sym string
+		n   int
+	}{{"!", st.Conflicted}, {"+", st.Staged}, {"~", st.Unstaged}, {"?", st.Untracked}} {
+		if p.n > 0 {
+			parts = append(parts, fmt.Sprintf("%s%d", p.sym, p.n))
+		}
+	}
+	if len(parts) == 0 {
+		parts = append(parts, "clean")
+	}
+	if st.Upstream != "" {
+		parts = append(parts, fmt.Sprintf("↑%d ↓%d", st.Ahead, st.Behind))
+	}
+	return strings.Join(parts, " ")
+}
+
+// resolveAdd decides how `add` gets branch, with the same resolution as the
+// branch picker: an existing local branch is checked out (no fromRef), a
+// remote-only branch gets a local tracking branch (fromRef is the remote branch,
+// remote its remote), and anything else is created new from from, defaulting to
+// the default branch.
+func resolveAdd(branch, from string) (fromRef, remote string, err error) {
+	switch {
+	case from != "":
+		if git.BranchExists(branch) {
+			return "", "", fmt.Errorf("branch %s already exists; omit --from to check it out", branch)
+		}
+		return from, "", nil
+	case git.BranchExists(branch):
+		return "", "", nil
+	}
+	brs, err := git.ListBranchesDetailed()
+	if err != nil {
+		return "", "", err
+	}
+	for _, b := range brs {
+		if b.IsRemote && b.Name == branch {
+			return b.RemoteRef, b.Remote, nil
+		}
+	}
+	fromRef, err = git.DefaultStartPoint()
+	return fromRef, "", err
+}
+
+func (c *runner) add(args []string) error {
+	fs := c.newFlags("add")
+	from := fs.String("from", "", "start a new branch from this ref (default: the default branch)")
+	path := fs.String("path", "", "worktree directory (default: from the path template)")
+	asJSON := fs.Bool("json", false, "print JSON")
+	noHooks := fs.Bool("no-hooks", false, "skip the post-create hooks")
+	pos, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) != 1 {
+		return fmt.Errorf("expected exactly one branch name")
+	}
+	branch := pos[0]
+	fromRef, remote, err := resolveAdd(branch, *from)
+	if err != nil {
+		return err
+	}
+	if fromRef != "" && remote == "" {
+		// A brand-new branch, so it has to follow the naming policy
+		n, err := branchname.New(c.cfg.BranchTemplate, c.cfg.BranchPattern)
+		if err != nil {
+			return err
+		}
+		if err := n.Check(branch); err != nil {
+			return err
+		}
+	}
+
+	target := *path
+	if target == "" {
+		target, err = git.WorktreePath(c.cfg.PathTemplate, branch, remote)
+	} else {
+		target, err = git.AbsWorktreePath(target)
+	}
+	if err != nil {
+		return err
+	}
+	if fromRef != "" {
+		err = git.CreateWorktreeFromRef(branch, target, fromRef)
+	} else {
+		err = git.CreateWorktree(branch, target, false)
+	}
+	if err != nil {
+		return err
+	}
+	if len(c.cfg.Copy) > 0 || len(c.cfg.Symlink) > 0 {
+		src, err := git.MainWorktreeRoot()
+		if err != nil {
+			return fmt.Errorf("could not copy files (worktree kept): %w", err)
+		}
+		r, err := seed.Apply(src, target, c.cfg.Copy, c.cfg.Symlink)
+		if !r.Empty() {
+			fmt.Fprintf(c.stderr, "%s: %s\n", filepath.Base(target), r.Summary())
+		}
+		if err != nil {
+			return fmt.Errorf("could not copy files (worktree kept): %w", err)
+		}
+	}
+	if *asJSON {
+		err = c.writeJSON(struct {
+			Name   string `json:"name"`
+			Path   string `json:"path"`
+			Branch string `json:"branch"`
+			From   string `json:"from,omitempty"`
+		}{filepath.Base(target), target, branch, fromRef})
+	} else {
+		_, err = fmt.Fprintln(c.stdout, target)
+	}
+	if err != nil || *noHooks || len(c.cfg.PostCreate) == 0 {
+		return err
+	}
+	// Hook output goes to stderr so stdout stays just the path (or JSON)
+	err = hook.Run(target, c.cfg.PostCreate, func(line string) { fmt.Fprintln(c.stderr, line) })
+	if err != nil {
+		return fmt.Errorf("post-create hook failed (worktree kept): %w", err)
+	}
+	return nil
+}
+
+func (c *runner) remove(args []string) error {
+	fs := c.newFlags("remove")
+	force := fs.Bool("force", false, "remove even with uncommitted changes, discarding them")
+	deleteBranch := fs.Bool("delete-branch", false, "also delete the worktree's branch")
+	forceBranch := fs.Bool("force-branch", false, "delete the branch even if it isn't merged (implies --delete-branch)")
+	pos, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) != 1 {
+		return fmt.Errorf("expected exactly one worktree")
+	}
+	wt, err := findWorktree(pos[0])
+	if err != nil {
+		return err
+	}
+	if wt.IsMain {
+		return fmt.Errorf("cannot remove the main worktree")
+	}
+	if err := git.RemoveWorktree(wt.Path, *force); err != nil {
+		if files, ferr := git.ChangedFiles(wt.Path); !*force && ferr == nil && len(files) > 0 {
+			noun := "files"
+			if len(files) == 1 {
+				noun = "file"
+			}
+			return fmt.Errorf("%s has %d changed %s (use --force to discard them):\n%s",
+				filepath.Base(wt.Path), len(files), noun, strings.Join(files, "\n"))
+		}
+		return err
+	}
+	fmt.Fprintf(c.stdout, "Removed worktree %s\n", filepath.Base(wt.Path))
+	if c.cfg.KillSessionOnDelete {
+		name := mux.SessionName(wt.Path)
+		for _, k := range mux.Live(wt.Path) {
+			if err := mux.KillSession(k, name); err != nil {
+				fmt.Fprintln(c.stderr, err)
+				continue
+			}
+			fmt.Fprintf(c.stdout, "Killed %s session %s\n", k, name)
+		}
+	}
+	if *deleteBranch || *forceBranch {
+		b := wt.BranchName()
+		if b == "" {
+			return fmt.Errorf("%s has no branch to delete", filepath.Base(wt.Path))
+		}
+		if err := git.DeleteBranch(b, *forceBranch); err != nil {
+			if errors.Is(err, git.ErrBranchNotMerged) {
+				return fmt.Errorf("%s: %w (use --force-branch to delete it anyway)", b, err)
+			}
+			return err
+		}
+		fmt.Fprintf(c.stdout, "Deleted branch %s\n", b)
+	}
+	return nil
+}
+
+func (c *runner) open(args []string) error {
+	fs := c.newFlags("open")
+	pos, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) != 1 {
+		return fmt.Errorf("expected exactly one worktree")
+	}
+	wt, err := findWorktree(pos[0])
+	if err != nil {
+		return err
+	}
+	cmd, err := editor.Command(c.cfg.Editor, wt.Path)
+	if err != nil {
+		return err
+	}
+	if err := cmd.Run(); err != nil {
+		var ee *exec.ExitError
+		if errors.As(err, &ee) {
+			return &exitError{code: ee.ExitCode()}
+		}
+		return err
+	}
+	return nil
+}
+
+// findWorktree resolves query to a worktree by path, then folder name, then branch.
+func findWorktree(query string) (git.Worktree, error) {
+	wts, err := git.ListWorktrees()
+	if err != nil {
+		return git.Worktree{}, err
+	}
+	if abs, err := filepath.Abs(query); err == nil {
+		for _, wt := range wts {
+			if filepath.Clean(wt.Path) == abs {
+				return wt, nil
+			}
+		}
+	}
+	for _, match := range []func(git.Worktree) bool{
+		func(wt git.Worktree) bool { return filepath.Base(wt.Path) == query },
+		func(wt git.Worktree) bool { return wt.BranchName() != "" && wt.BranchName() == query },
+	} {
+		var found []git.Worktree
+		for _, wt := range wts {
+			if match(wt) {
+				found = append(found, wt)
+			}
+		}
+		switch len(found) {
+		case 0:
+			continue
+		case 1:
+			return found[0], nil
+		}
+		var paths []string
+		for _, wt := range found {
+			paths = append(paths, wt.Path)
+		}
+		return git.Worktree{}, fmt.Errorf("%q matches several worktrees; use a path:\n%s", query, strings.Join(paths, "\n"))
+	}
+	return git.Worktree{}, fmt.Errorf("no worktree named %q", query)
+}
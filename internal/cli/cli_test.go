@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fredrikmwold/git-worktree-tui/internal/git"
+)
+
+// testRepo creates a repository with one commit on main, pushed to a bare
+// origin, and changes into it for the rest of the test.
+func testRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_AUTHOR_NAME", "Test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+	tmp := t.TempDir()
+	dir, remote := filepath.Join(tmp, "app"), filepath.Join(tmp, "origin.git")
+	run(t, tmp, "init", "-q", "--bare", remote)
+	run(t, tmp, "init", "-q", "--initial-branch=main", dir)
+	run(t, dir, "commit", "-q", "--allow-empty", "-m", "initial")
+	run(t, dir, "remote", "add", "origin", remote)
+	run(t, dir, "push", "-q", "origin", "main")
+	run(t, dir, "remote", "set-head", "origin", "main")
+	old, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(old) })
+	return dir
+}
+
+func run(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+}
+
+func TestResolveAdd(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(t *testing.T, dir string)
+		branch  string
+		from    string
+		ref     string // expected start point; "" checks out an existing branch
+		remote  string
+		wantErr string
+	}{
+		{
+			name:   "existing local branch",
+			setup:  func(t *testing.T, dir string) { run(t, dir, "branch", "feature/local") },
+			branch: "feature/local",
+		},
+		{
+			name: "remote-only branch",
+			setup: func(t *testing.T, dir string) {
+				run(t, dir, "push", "-q", "origin", "main:feature/remote")
+			},
+			branch: "feature/remote",
+			ref:    "origin/feature/remote",
+			remote: "origin",
+		},
+		{
+			name:   "new branch from the default branch",
+			branch: "feature/new",
+			ref:    "main",
+		},
+		{
+			name: "new branch without a local default branch",
+			setup: func(t *testing.T, dir string) {
+				// origin/HEAD still names main, which only exists on the remote
+				run(t, dir, "branch", "-q", "-m", "main", "master")
+			},
+			branch: "feature/new",
+			ref:    "origin/main",
+		},
+		{
+			name:   "new branch with --from",
+			branch: "feature/new",
+			from:   "HEAD~0",
+			ref:    "HEAD~0",
+		},
+		{
+			name:    "--from with an existing branch",
+			setup:   func(t *testing.T, dir string) { run(t, dir, "branch", "feature/local") },
+			branch:  "feature/local",
+			from:    "main",
+			wantErr: "already exists",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := testRepo(t)
+			if tt.setup != nil {
+				tt.setup(t, dir)
+			}
+			ref, remote, err := resolveAdd(tt.branch, tt.from)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("resolveAdd() error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ref != tt.ref || remote != tt.remote {
+				t.Fatalf("resolveAdd(%q, %q) = %q, %q; want %q, %q", tt.branch, tt.from, ref, remote, tt.ref, tt.remote)
+			}
+			// The result must be something git can create the worktree from
+			target := filepath.Join(t.TempDir(), "wt")
+			if ref != "" {
+				err = git.CreateWorktreeFromRef(tt.branch, target, ref)
+			} else {
+				err = git.CreateWorktree(tt.branch, target, false)
+			}
+			if err != nil {
+				t.Fatalf("creating the worktree: %v", err)
+			}
+		})
+	}
+}
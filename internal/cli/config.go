@@ -0,0 +1,44 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/fredrikmwold/git-worktree-tui/internal/config"
+	"github.com/fredrikmwold/git-worktree-tui/internal/git"
+)
+
+// config prints where settings are read from and the effective result.
+func (c *runner) config(args []string) error {
+	fs := c.newFlags("config")
+	pos, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) != 0 {
+		return fmt.Errorf("unexpected argument %q", pos[0])
+	}
+	fmt.Fprintln(c.stdout, "# Config files; later ones override earlier ones, and git config overrides both:")
+	if p, err := config.GlobalFile(); err == nil {
+		fmt.Fprintf(c.stdout, "#   %s%s\n", p, missing(p))
+	}
+	if root, err := git.MainWorktreeRoot(); err == nil {
+		p := filepath.Join(root, config.RepoFileName)
+		fmt.Fprintf(c.stdout, "#   %s%s\n", p, missing(p))
+	}
+	if note := c.cfg.UntrustedNote(); note != "" {
+		fmt.Fprintf(c.stdout, "# Note: %s\n", note)
+	}
+	fmt.Fprintln(c.stdout)
+	return config.Encode(c.stdout, c.cfg)
+}
+
+// missing returns " (not found)" when path does not exist.
+func missing(path string) string {
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return " (not found)"
+	}
+	return ""
+}
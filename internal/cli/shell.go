@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Shell wrappers run the TUI with --cd-file pointing at a temp file and cd into
+// whatever path it leaves there. With arguments they just pass through.
+const posixInit = `{{cmd}}() {
+  if [ "$#" -gt 0 ]; then
+    command worktree-tui "$@"
+    return
+  fi
+  local tmp dir
+  tmp="$(mktemp)" || return
+  command worktree-tui --cd-file "$tmp"
+  dir="$(cat -- "$tmp")"
+  rm -f -- "$tmp"
+  if [ -n "$dir" ] && [ -d "$dir" ]; then
+    cd -- "$dir" || return
+  fi
+}
+`
+
+const fishInit = `function {{cmd}}
+    if test (count $argv) -gt 0
+        command worktree-tui $argv
+        return
+    end
+    set -l tmp (mktemp); or return
+    command worktree-tui --cd-file $tmp
+    set -l dir (cat -- $tmp)
+    rm -f -- $tmp
+    if test -n "$dir"; and test -d "$dir"
+        cd -- $dir
+    end
+end
+`
+
+var validFuncName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+func (c *runner) init(args []string) error {
+	fs := c.newFlags("init")
+	name := fs.String("cmd", "wt", "name of the shell function to define")
+	pos, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) != 1 {
+		return fmt.Errorf("expected a shell: bash, zsh or fish")
+	}
+	if !validFuncName.MatchString(*name) {
+		return fmt.Errorf("invalid function name %q", *name)
+	}
+	var script string
+	switch pos[0] {
+	case "bash", "zsh":
+		script = posixInit
+	case "fish":
+		script = fishInit
+	default:
+		return fmt.Errorf("unsupported shell %q (want bash, zsh or fish)", pos[0])
+	}
+	fmt.Fprint(c.stdout, strings.ReplaceAll(script, "{{cmd}}", *name))
+	return nil
+}
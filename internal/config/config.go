@@ -0,0 +1,426 @@
+// Package config loads user settings for worktree-tui.
+//
+// Settings are layered; later layers override earlier ones:
+//
+//  1. built-in defaults
+//  2. the global file, $XDG_CONFIG_HOME/worktree-tui/config.toml
+//  3. the repository file, .worktree-tui.toml in the main worktree
+//  4. the `worktree-tui` git config section
+//
+// The files use the same key names as git config:
+//
+//	pathTemplate = "{{.RepoRoot}}/../{{.Repo}}.worktrees/{{.BranchSlug}}"
+//	postCreate = ["npm ci", "direnv allow"]
+//
+//	[action.tmux]
+//	command = "tmux new-window -c {{.Path}}"
+//	mode = "background"
+//
+//	[keys]
+//	delete = ["x", "delete"]
+//
+// New branch names can follow a convention: a prefix picked while typing, a
+// template over branchname.Vars and a policy every new branch must match:
+//
+//	branchPrefixes = ["feat/", "fix/", "chore/"]
+//	branchTemplate = "{{.Prefix}}{{.Ticket}}-{{.Slug}}"
+//	branchPattern = "(feat|fix|chore)/[A-Z]+-[0-9]+-[a-z0-9-]+"
+//
+// A repository file can only set commands (editor, postCreate and actions)
+// once its repository is trusted, from the global file or git config:
+//
+//	trustedRepos = ["~/src/work/*"]
+//
+// Custom themes are only read from the files:
+//
+//	theme = "mine"
+//
+//	[themes.mine]
+//	extends = "latte"
+//	mauve = "#ea76cb"
+//
+// In git config, settings can be set for one repository (`git config`) or for
+// all of them (`git config --global`):
+//
+//	git config --global worktree-tui.pathTemplate '{{.RepoRoot}}/../{{.Repo}}.worktrees/{{.BranchSlug}}'
+//	git config --global worktree-tui.stayAfterEditor true
+//	git config --global worktree-tui.editor 'code --wait {{.Path}}'
+//	git config --global worktree-tui.killSessionOnDelete true
+//	git config --global worktree-tui.theme latte
+//
+// Post-create hooks are multi-valued and run in order:
+//
+//	git config --add worktree-tui.postCreate 'npm ci'
+//	git config --add worktree-tui.postCreate 'direnv allow'
+//
+// Branch prefixes are multi-valued as well:
+//
+//	git config --add worktree-tui.branchPrefixes feat/
+//	git config --add worktree-tui.branchPrefixes fix/
+//	git config worktree-tui.branchPattern '(feat|fix)/.+'
+//
+// Untracked files to bring into new worktrees are multi-valued globs too:
+//
+//	git config --add worktree-tui.copy .env
+//	git config --add worktree-tui.symlink 'certs/*.pem'
+//
+// Open actions use one subsection per action:
+//
+//	git config worktree-tui.action.tmux.command 'tmux new-window -c {{.Path}}'
+//	git config worktree-tui.action.tmux.mode background
+//
+// Trusted repositories are multi-valued and added to those in the global file:
+//
+//	git config --global --add worktree-tui.trustedRepos ~/src/app
+//
+// Key bindings are remapped by name (see keymap.Names):
+//
+//	git config --global worktree-tui.keys.delete x
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/fredrikmwold/git-worktree-tui/internal/action"
+	"github.com/fredrikmwold/git-worktree-tui/internal/branchname"
+	"github.com/fredrikmwold/git-worktree-tui/internal/editor"
+	"github.com/fredrikmwold/git-worktree-tui/internal/git"
+	"github.com/fredrikmwold/git-worktree-tui/internal/keymap"
+	"github.com/fredrikmwold/git-worktree-tui/internal/seed"
+	"github.com/fredrikmwold/git-worktree-tui/internal/theme"
+)
+
+// Config holds all user settings.
+type Config struct {
+	// PathTemplate is a text/template for new worktree directories; see git.PathVars.
+	PathTemplate string
+	// Actions are user-defined open actions; they replace built-ins of the same name.
+	Actions []action.Action
+	// Editor is a command template for opening worktrees; empty uses $VISUAL/$EDITOR.
+	Editor string
+	// StayAfterEditor returns to the worktree list when the editor exits instead of quitting.
+	StayAfterEditor bool
+	// KillSessionOnDelete ends a worktree's tmux/zellij session when the worktree is removed.
+	KillSessionOnDelete bool
+	// PostCreate are shell commands run in each new worktree, in order.
+	PostCreate []string
+	// Copy and Symlink are glob patterns, relative to the main worktree, of files
+	// to copy or symlink into each new worktree.
+	Copy    []string
+	Symlink []string
+	// BranchPrefixes are offered while typing a new branch name, e.g. "feat/".
+	BranchPrefixes []string
+	// BranchTemplate is a text/template turning a typed title into a branch name;
+	// see branchname.Vars. Empty keeps valid names as typed and slugifies the rest.
+	BranchTemplate string
+	// BranchPattern is a regular expression new branch names must match in full.
+	BranchPattern string
+	// Keys are the TUI key bindings, defaults overridden per binding.
+	Keys keymap.Map
+	// Theme names the color theme: theme.Auto, theme.None, a built-in or one of Themes.
+	Theme string
+	// Themes are user-defined palettes by name.
+	Themes map[string]theme.Palette
+	// TrustedRepos are main worktree paths (globs allowed, ~ expanded) whose
+	// repository file may set commands. Only the global file and git config set them.
+	TrustedRepos []string
+	// Untrusted lists the command keys ignored from UntrustedFile, the
+	// repository file of a repository not in TrustedRepos.
+	Untrusted     []string
+	UntrustedFile string
+}
+
+// Default returns the built-in settings.
+func Default() Config {
+	return Config{PathTemplate: git.DefaultPathTemplate, Keys: keymap.Default(), Theme: theme.Auto}
+}
+
+// Load returns the default settings overridden by the config files and git config.
+func Load() (Config, error) {
+	cfg := Default()
+	paths, err := Files()
+	if err != nil {
+		return cfg, err
+	}
+	// Read before the files so the repository file can be checked against it
+	trusted, err := git.ConfigGetAll("worktree-tui.trustedRepos")
+	if err != nil {
+		return cfg, err
+	}
+	for _, p := range paths {
+		if err := loadFile(&cfg, p, trusted); err != nil {
+			return cfg, err
+		}
+	}
+	cfg.TrustedRepos = append(cfg.TrustedRepos, trusted...)
+	if err := loadGit(&cfg); err != nil {
+		return cfg, err
+	}
+	if err := cfg.Keys.Validate(); err != nil {
+		return cfg, fmt.Errorf("keys: %w", err)
+	}
+	if err := validateTheme(cfg.Theme, cfg.Themes); err != nil {
+		return cfg, fmt.Errorf("theme: %w", err)
+	}
+	return cfg, nil
+}
+
+// loadGit applies the settings in the worktree-tui git config section.
+func loadGit(cfg *Config) error {
+	if err := setString(&cfg.PathTemplate, "worktree-tui.pathTemplate"); err != nil {
+		return err
+	}
+	if err := validatePathTemplate(cfg.PathTemplate); err != nil {
+		return fmt.Errorf("worktree-tui.pathTemplate: %w", err)
+	}
+	if err := setString(&cfg.Editor, "worktree-tui.editor"); err != nil {
+		return err
+	}
+	if err := editor.Validate(cfg.Editor); err != nil {
+		return fmt.Errorf("worktree-tui.editor: %w", err)
+	}
+	if err := setBool(&cfg.StayAfterEditor, "worktree-tui.stayAfterEditor"); err != nil {
+		return err
+	}
+	if err := setBool(&cfg.KillSessionOnDelete, "worktree-tui.killSessionOnDelete"); err != nil {
+		return err
+	}
+	if err := setList(&cfg.PostCreate, "worktree-tui.postCreate"); err != nil {
+		return err
+	}
+	if err := setList(&cfg.Copy, "worktree-tui.copy"); err != nil {
+		return err
+	}
+	if err := setList(&cfg.Symlink, "worktree-tui.symlink"); err != nil {
+		return err
+	}
+	if err := validatePatterns(cfg.Copy); err != nil {
+		return fmt.Errorf("worktree-tui.copy: %w", err)
+	}
+	if err := validatePatterns(cfg.Symlink); err != nil {
+		return fmt.Errorf("worktree-tui.symlink: %w", err)
+	}
+	if err := setList(&cfg.BranchPrefixes, "worktree-tui.branchPrefixes"); err != nil {
+		return err
+	}
+	if err := validatePrefixes(cfg.BranchPrefixes); err != nil {
+		return fmt.Errorf("worktree-tui.branchPrefixes: %w", err)
+	}
+	if err := setString(&cfg.BranchTemplate, "worktree-tui.branchTemplate"); err != nil {
+		return err
+	}
+	if _, err := branchname.New(cfg.BranchTemplate, ""); err != nil {
+		return fmt.Errorf("worktree-tui.branchTemplate: %w", err)
+	}
+	if err := setString(&cfg.BranchPattern, "worktree-tui.branchPattern"); err != nil {
+		return err
+	}
+	if _, err := branchname.New("", cfg.BranchPattern); err != nil {
+		return fmt.Errorf("worktree-tui.branchPattern: %w", err)
+	}
+	if err := setString(&cfg.Theme, "worktree-tui.theme"); err != nil {
+		return err
+	}
+	if err := loadKeys(&cfg.Keys); err != nil {
+		return err
+	}
+	acts, err := loadActions()
+	if err != nil {
+		return err
+	}
+	cfg.Actions = action.Merge(cfg.Actions, acts)
+	return nil
+}
+
+// UntrustedNote explains which repository file settings were ignored and how
+// to trust the repository, or returns "" when nothing was ignored.
+func (c Config) UntrustedNote() string {
+	if len(c.Untrusted) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("ignored %s from untrusted %s; trust it with: git config --global --add worktree-tui.trustedRepos %s",
+		strings.Join(c.Untrusted, ", "), c.UntrustedFile, filepath.Dir(c.UntrustedFile))
+}
+
+// trustedRepo reports whether the main worktree at dir matches one of patterns.
+func trustedRepo(dir string, patterns []string) bool {
+	home, _ := os.UserHomeDir()
+	for _, p := range patterns {
+		p = strings.TrimSpace(p)
+		if home != "" && (p == "~" || strings.HasPrefix(p, "~/")) {
+			p = filepath.Join(home, strings.TrimPrefix(p, "~"))
+		}
+		if p == "" {
+			continue
+		}
+		p = filepath.Clean(p)
+		if ok, _ := filepath.Match(p, dir); ok || p == dir {
+			return true
+		}
+	}
+	return false
+}
+
+// loadKeys applies worktree-tui.keys.<binding> entries; repeat a key with
+// `git config --add` to bind several keys.
+func loadKeys(km *keymap.Map) error {
+	entries, err := git.ConfigGetRegexp(`^worktree-tui\.keys\.`)
+	if err != nil {
+		return err
+	}
+	var order []string
+	keys := map[string][]string{}
+	for _, e := range entries {
+		name := strings.TrimPrefix(e.Key, "worktree-tui.keys.")
+		if _, ok := keys[name]; !ok {
+			order = append(order, name)
+		}
+		keys[name] = append(keys[name], strings.TrimSpace(e.Value))
+	}
+	for _, name := range order {
+		if err := km.Set(name, keys[name]); err != nil {
+			return fmt.Errorf("worktree-tui.keys.%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func validatePathTemplate(tmpl string) error {
+	_, err := template.New("path").Parse(tmpl)
+	return err
+}
+
+func validateTheme(name string, custom map[string]theme.Palette) error {
+	if name == theme.Auto || name == theme.None {
+		return nil
+	}
+	if _, ok := theme.Lookup(name, custom); !ok {
+		return fmt.Errorf("unknown theme %q (want one of %s)", name, strings.Join(theme.Names(custom), ", "))
+	}
+	return nil
+}
+
+// validatePrefixes checks that each prefix can start a branch name.
+func validatePrefixes(prefixes []string) error {
+	for _, p := range prefixes {
+		if err := git.ValidateBranchName(p + "x"); err != nil {
+			return fmt.Errorf("prefix %q: %w", p, err)
+		}
+	}
+	return nil
+}
+
+func validatePatterns(patterns []string) error {
+	for _, p := range patterns {
+		if err := seed.Validate(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setList replaces dst with all non-empty values of the multi-valued key, if any.
+func setList(dst *[]string, key string) error {
+	vals, err := git.ConfigGetAll(key)
+	if err != nil {
+		return err
+	}
+	var list []string
+	for _, v := range vals {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+	if len(list) > 0 {
+		*dst = list
+	}
+	return nil
+}
+
+// setBool overwrites dst with the value of key when it is set, accepting the
+// same spellings as git (true/yes/on/1 and false/no/off/0).
+func setBool(dst *bool, key string) error {
+	v, err := git.ConfigGet(key)
+	if err != nil || v == "" {
+		return err
+	}
+	switch strings.ToLower(v) {
+	case "true", "yes", "on", "1":
+		*dst = true
+	case "false", "no", "off", "0":
+		*dst = false
+	default:
+		return fmt.Errorf("%s: invalid boolean %q", key, v)
+	}
+	return nil
+}
+
+const actionPrefix = "worktree-tui.action."
+
+// loadActions reads worktree-tui.action.<name>.{command,mode} entries.
+// Actions without a mode stay in the TUI after their command exits.
+func loadActions() ([]action.Action, error) {
+	entries, err := git.ConfigGetRegexp(`^worktree-tui\.action\.`)
+	if err != nil {
+		return nil, err
+	}
+	type raw struct{ command, mode string }
+	var order []string
+	byName := map[string]*raw{}
+	for _, e := range entries {
+		rest := strings.TrimPrefix(e.Key, actionPrefix)
+		dot := strings.LastIndex(rest, ".")
+		if dot <= 0 {
+			return nil, fmt.Errorf("%s: expected %s<name>.command or .mode", e.Key, actionPrefix)
+		}
+		name, key := rest[:dot], rest[dot+1:]
+		r, ok := byName[name]
+		if !ok {
+			r = &raw{}
+			byName[name] = r
+			order = append(order, name)
+		}
+		switch key {
+		case "command":
+			r.command = e.Value
+		case "mode":
+			r.mode = e.Value
+		default:
+			return nil, fmt.Errorf("%s: unknown key %q (want command or mode)", e.Key, key)
+		}
+	}
+	var acts []action.Action
+	for _, name := range order {
+		r := byName[name]
+		mode := action.ModeStay
+		if r.mode != "" {
+			m, err := action.ParseMode(r.mode)
+			if err != nil {
+				return nil, fmt.Errorf("%s%s.mode: %w", actionPrefix, name, err)
+			}
+			mode = m
+		}
+		a, err := action.New(name, r.command, mode)
+		if err != nil {
+			return nil, fmt.Errorf("%s%s.command: %w", actionPrefix, name, err)
+		}
+		acts = append(acts, a)
+	}
+	return acts, nil
+}
+
+// setString overwrites dst with the value of key when it is set.
+func setString(dst *string, key string) error {
+	v, err := git.ConfigGet(key)
+	if err != nil {
+		return err
+	}
+	if v != "" {
+		*dst = v
+	}
+	return nil
+}
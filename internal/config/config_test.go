@@ -0,0 +1,37 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTrustedRepo(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	app := filepath.Join(home, "src", "work", "app")
+	tests := []struct {
+		name     string
+		dir      string
+		patterns []string
+		want     bool
+	}{
+		{name: "none", dir: app, want: false},
+		{name: "exact", dir: app, patterns: []string{app}, want: true},
+		{name: "trailing slash", dir: app, patterns: []string{app + string(filepath.Separator)}, want: true},
+		{name: "home", dir: app, patterns: []string{"~/src/work/app"}, want: true},
+		{name: "glob", dir: app, patterns: []string{"~/src/work/*"}, want: true},
+		{name: "glob is one level", dir: filepath.Join(app, "sub"), patterns: []string{"~/src/work/*"}, want: false},
+		{name: "parent isn't enough", dir: app, patterns: []string{"~/src"}, want: false},
+		{name: "other repository", dir: app, patterns: []string{"~/src/work/api"}, want: false},
+		{name: "blank entries", dir: app, patterns: []string{"", "  "}, want: false},
+		{name: "any of several", dir: app, patterns: []string{"~/oss/*", " ~/src/work/app "}, want: true},
+	}
+	for _, tt := range tests {
+		if got := trustedRepo(tt.dir, tt.patterns); got != tt.want {
+			t.Errorf("%s: trustedRepo(%q, %q) = %v, want %v", tt.name, tt.dir, tt.patterns, got, tt.want)
+		}
+	}
+}
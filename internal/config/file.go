@@ -0,0 +1,398 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/fredrikmwold/git-worktree-tui/internal/action"
+	"github.com/fredrikmwold/git-worktree-tui/internal/branchname"
+	"github.com/fredrikmwold/git-worktree-tui/internal/editor"
+	"github.com/fredrikmwold/git-worktree-tui/internal/git"
+	"github.com/fredrikmwold/git-worktree-tui/internal/theme"
+)
+
+// RepoFileName is the per-repository config file, looked up in the main worktree.
+const RepoFileName = ".worktree-tui.toml"
+
+// file mirrors the TOML layout. Pointers and nil slices tell unset keys apart
+// from zero values so that a file only overrides what it mentions.
+type file struct {
+	PathTemplate        *string               `toml:"pathTemplate"`
+	Editor              *string               `toml:"editor"`
+	StayAfterEditor     *bool                 `toml:"stayAfterEditor"`
+	KillSessionOnDelete *bool                 `toml:"killSessionOnDelete"`
+	PostCreate          []string              `toml:"postCreate"`
+	Copy                []string              `toml:"copy"`
+	Symlink             []string              `toml:"symlink"`
+	BranchPrefixes      []string              `toml:"branchPrefixes"`
+	BranchTemplate      *string               `toml:"branchTemplate"`
+	BranchPattern       *string               `toml:"branchPattern"`
+	Action              map[string]fileAction `toml:"action"`
+	Keys                map[string]keyList    `toml:"keys"`
+	Theme               *string               `toml:"theme"`
+	Themes              map[string]fileTheme  `toml:"themes"`
+	TrustedRepos        []string              `toml:"trustedRepos"`
+}
+
+// fileTheme is a palette: color names to colors, plus the palette to start
+// from under "extends" (default mocha).
+type fileTheme map[string]string
+
+// keyList accepts a single key or an array of keys.
+type keyList []string
+
+func (k *keyList) UnmarshalTOML(v any) error {
+	switch v := v.(type) {
+	case string:
+		*k = keyList{v}
+		return nil
+	case []any:
+		for _, e := range v {
+			s, ok := e.(string)
+			if !ok {
+				return fmt.Errorf("keys must be strings, got %v", e)
+			}
+			*k = append(*k, s)
+		}
+		return nil
+	}
+	return fmt.Errorf("want a key or an array of keys, got %v", v)
+}
+
+type fileAction struct {
+	Command string `toml:"command"`
+	Mode    string `toml:"mode"`
+}
+
+// knownKeys lists the valid keys per table, for "did you mean" suggestions.
+var knownKeys = map[string][]string{
+	"":       {"pathTemplate", "editor", "stayAfterEditor", "killSessionOnDelete", "postCreate", "copy", "symlink", "branchPrefixes", "branchTemplate", "branchPattern", "action", "keys", "theme", "themes", "trustedRepos"},
+	"action": {"command", "mode"},
+}
+
+// GlobalFile returns the path of the global config file, whether or not it
+// exists: $XDG_CONFIG_HOME/worktree-tui/config.toml, defaulting to ~/.config
+// (%AppData% on Windows).
+func GlobalFile() (string, error) {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" && runtime.GOOS != "windows" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	if dir == "" {
+		var err error
+		if dir, err = os.UserConfigDir(); err != nil {
+			return "", err
+		}
+	}
+	return filepath.Join(dir, "worktree-tui", "config.toml"), nil
+}
+
+// Files returns the config files that exist, in the order they are applied:
+// the global file, then the repository's. Outside a repository only the
+// global file is considered.
+func Files() ([]string, error) {
+	var paths []string
+	if p, err := GlobalFile(); err == nil {
+		paths = append(paths, p)
+	}
+	if root, err := git.MainWorktreeRoot(); err == nil {
+		paths = append(paths, filepath.Join(root, RepoFileName))
+	}
+	var found []string
+	for _, p := range paths {
+		_, err := os.Stat(p)
+		switch {
+		case err == nil:
+			found = append(found, p)
+		case !errors.Is(err, fs.ErrNotExist):
+			return nil, err
+		}
+	}
+	return found, nil
+}
+
+// loadFile applies the settings in the TOML file at path, rejecting unknown
+// keys and invalid values with the file and key in the message. A repository
+// file only sets commands when its repository is trusted by cfg.TrustedRepos
+// or trusted; otherwise they are recorded in cfg.Untrusted and skipped.
+func loadFile(cfg *Config, path string, trusted []string) error {
+	var f file
+	md, err := toml.DecodeFile(path, &f)
+	if err != nil {
+		var perr toml.ParseError
+		if errors.As(err, &perr) {
+			return fmt.Errorf("%s: %s", path, perr.ErrorWithPosition())
+		}
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	if undecoded := md.Undecoded(); len(undecoded) > 0 {
+		var msgs, reported []string
+		for _, k := range undecoded {
+			// Skip the contents of an unknown table already reported
+			skip := false
+			for _, r := range reported {
+				skip = skip || strings.HasPrefix(k.String(), r+".")
+			}
+			if !skip {
+				msgs = append(msgs, unknownKey(k))
+				reported = append(reported, k.String())
+			}
+		}
+		return fmt.Errorf("%s: %s", path, strings.Join(msgs, "; "))
+	}
+	fail := func(key string, err error) error { return fmt.Errorf("%s: %s: %w", path, key, err) }
+	if filepath.Base(path) == RepoFileName {
+		if f.TrustedRepos != nil {
+			return fail("trustedRepos", fmt.Errorf("only read from the global config file and git config"))
+		}
+		if !trustedRepo(filepath.Dir(path), append(cfg.TrustedRepos, trusted...)) {
+			f.skipCommands(cfg, path)
+		}
+	}
+	if f.PathTemplate != nil {
+		if err := validatePathTemplate(*f.PathTemplate); err != nil {
+			return fail("pathTemplate", err)
+		}
+		cfg.PathTemplate = *f.PathTemplate
+	}
+	if f.Editor != nil {
+		if err := editor.Validate(*f.Editor); err != nil {
+			return fail("editor", err)
+		}
+		cfg.Editor = *f.Editor
+	}
+	if f.StayAfterEditor != nil {
+		cfg.StayAfterEditor = *f.StayAfterEditor
+	}
+	if f.KillSessionOnDelete != nil {
+		cfg.KillSessionOnDelete = *f.KillSessionOnDelete
+	}
+	if f.PostCreate != nil {
+		cfg.PostCreate = f.PostCreate
+	}
+	if f.Copy != nil {
+		if err := validatePatterns(f.Copy); err != nil {
+			return fail("copy", err)
+		}
+		cfg.Copy = f.Copy
+	}
+	if f.Symlink != nil {
+		if err := validatePatterns(f.Symlink); err != nil {
+			return fail("symlink", err)
+		}
+		cfg.Symlink = f.Symlink
+	}
+	if f.BranchPrefixes != nil {
+		if err := validatePrefixes(f.BranchPrefixes); err != nil {
+			return fail("branchPrefixes", err)
+		}
+		cfg.BranchPrefixes = f.BranchPrefixes
+	}
+	if f.BranchTemplate != nil {
+		if _, err := branchname.New(*f.BranchTemplate, ""); err != nil {
+			return fail("branchTemplate", err)
+		}
+		cfg.BranchTemplate = *f.BranchTemplate
+	}
+	if f.BranchPattern != nil {
+		if _, err := branchname.New("", *f.BranchPattern); err != nil {
+			return fail("branchPattern", err)
+		}
+		cfg.BranchPattern = *f.BranchPattern
+	}
+	for _, name := range sortedKeys(f.Keys) {
+		if err := cfg.Keys.Set(name, f.Keys[name]); err != nil {
+			return fail("keys."+name, err)
+		}
+	}
+	if f.Theme != nil {
+		cfg.Theme = *f.Theme
+	}
+	// Themes in file order, so one can extend another defined above it
+	for _, k := range md.Keys() {
+		if len(k) != 2 || k[0] != "themes" {
+			continue
+		}
+		p, err := f.Themes[k[1]].palette(cfg.Themes)
+		if err != nil {
+			return fail("themes."+k[1], err)
+		}
+		if cfg.Themes == nil {
+			cfg.Themes = map[string]theme.Palette{}
+		}
+		cfg.Themes[k[1]] = p
+	}
+	// Keep actions in file order; the map itself is unordered
+	var names []string
+	for _, k := range md.Keys() {
+		if len(k) == 2 && k[0] == "action" {
+			names = append(names, k[1])
+		}
+	}
+	var acts []action.Action
+	for _, name := range names {
+		fa, ok := f.Action[name]
+		if !ok {
+			continue
+		}
+		mode := action.ModeStay
+		if fa.Mode != "" {
+			m, err := action.ParseMode(fa.Mode)
+			if err != nil {
+				return fail("action."+name+".mode", err)
+			}
+			mode = m
+		}
+		a, err := action.New(name, fa.Command, mode)
+		if err != nil {
+			return fail("action."+name+".command", err)
+		}
+		acts = append(acts, a)
+	}
+	cfg.Actions = action.Merge(cfg.Actions, acts)
+	if f.TrustedRepos != nil {
+		cfg.TrustedRepos = f.TrustedRepos
+	}
+	return nil
+}
+
+// skipCommands drops the settings that run commands, noting them in cfg.
+func (f *file) skipCommands(cfg *Config, path string) {
+	var keys []string
+	if f.Editor != nil {
+		keys = append(keys, "editor")
+	}
+	if f.PostCreate != nil {
+		keys = append(keys, "postCreate")
+	}
+	for _, name := range sortedKeys(f.Action) {
+		keys = append(keys, "action."+name)
+	}
+	f.Editor, f.PostCreate, f.Action = nil, nil, nil
+	if len(keys) > 0 {
+		cfg.Untrusted, cfg.UntrustedFile = keys, path
+	}
+}
+
+// Encode writes cfg in the config file format, e.g. to show the effective settings.
+func Encode(w io.Writer, cfg Config) error {
+	f := file{
+		PathTemplate:        &cfg.PathTemplate,
+		Editor:              &cfg.Editor,
+		StayAfterEditor:     &cfg.StayAfterEditor,
+		KillSessionOnDelete: &cfg.KillSessionOnDelete,
+		PostCreate:          cfg.PostCreate,
+		Copy:                cfg.Copy,
+		Symlink:             cfg.Symlink,
+		BranchPrefixes:      cfg.BranchPrefixes,
+		BranchTemplate:      &cfg.BranchTemplate,
+		BranchPattern:       &cfg.BranchPattern,
+	}
+	for _, a := range cfg.Actions {
+		if f.Action == nil {
+			f.Action = map[string]fileAction{}
+		}
+		f.Action[a.Name] = fileAction{Command: a.Command, Mode: string(a.Mode)}
+	}
+	f.Theme = &cfg.Theme
+	for name, p := range cfg.Themes {
+		if f.Themes == nil {
+			f.Themes = map[string]fileTheme{}
+		}
+		f.Themes[name] = p.Colors()
+	}
+	f.TrustedRepos = cfg.TrustedRepos
+	f.Keys = map[string]keyList{}
+	for name, keys := range cfg.Keys.Bound() {
+		f.Keys[name] = keys
+	}
+	return toml.NewEncoder(w).Encode(f)
+}
+
+// palette builds the theme on top of its "extends" palette.
+func (t fileTheme) palette(custom map[string]theme.Palette) (theme.Palette, error) {
+	base := "mocha"
+	if e, ok := t["extends"]; ok {
+		base = e
+	}
+	p, ok := theme.Lookup(base, custom)
+	if !ok {
+		return p, fmt.Errorf("extends: unknown theme %q", base)
+	}
+	for _, name := range sortedKeys(t) {
+		if name == "extends" {
+			continue
+		}
+		if err := p.Set(name, t[name]); err != nil {
+			return p, err
+		}
+	}
+	return p, nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// unknownKey describes an unexpected key, suggesting the closest valid one.
+func unknownKey(k toml.Key) string {
+	msg := fmt.Sprintf("unknown key %q", k.String())
+	name, candidates := k[0], knownKeys[""]
+	if sub, ok := knownKeys[k[0]]; ok && len(k) > 2 {
+		// An unknown key inside a known table such as [action.<name>]
+		name, candidates = k[len(k)-1], sub
+	}
+	if s := suggest(name, candidates); s != "" {
+		msg += fmt.Sprintf(" (did you mean %q?)", s)
+	}
+	return msg
+}
+
+// suggest returns the candidate closest to key, or "" if none is close.
+func suggest(key string, candidates []string) string {
+	best, bestDist := "", 3
+	for _, c := range candidates {
+		if d := distance(strings.ToLower(key), strings.ToLower(c)); d < bestDist {
+			best, bestDist = c, d
+		}
+	}
+	return best
+}
+
+// distance is the Levenshtein edit distance between a and b.
+func distance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
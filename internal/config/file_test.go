@@ -0,0 +1,145 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+)
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"editor", "editor", 0},
+		{"", "copy", 4},
+		{"edtor", "editor", 1},
+		{"pathTemplat", "pathTemplate", 1},
+		{"symlinks", "symlink", 1},
+		{"kitten", "sitting", 3},
+	}
+	for _, tt := range tests {
+		if got := distance(tt.a, tt.b); got != tt.want {
+			t.Errorf("distance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := distance(tt.b, tt.a); got != tt.want {
+			t.Errorf("distance(%q, %q) = %d, want %d", tt.b, tt.a, got, tt.want)
+		}
+	}
+}
+
+func TestUnknownKey(t *testing.T) {
+	tests := []struct {
+		key  toml.Key
+		want string
+	}{
+		{toml.Key{"edtor"}, `unknown key "edtor" (did you mean "editor"?)`},
+		{toml.Key{"PathTemplate"}, `unknown key "PathTemplate" (did you mean "pathTemplate"?)`},
+		{toml.Key{"postcreate"}, `unknown key "postcreate" (did you mean "postCreate"?)`},
+		{toml.Key{"actions"}, `unknown key "actions" (did you mean "action"?)`},
+		{toml.Key{"colors"}, `unknown key "colors"`},
+		{toml.Key{"action", "tmux", "comand"}, `unknown key "action.tmux.comand" (did you mean "command"?)`},
+		{toml.Key{"action", "tmux", "shell"}, `unknown key "action.tmux.shell"`},
+	}
+	for _, tt := range tests {
+		if got := unknownKey(tt.key); got != tt.want {
+			t.Errorf("unknownKey(%v) = %s, want %s", tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		repo    bool     // write the repository file instead of a global one
+		trusted []string // trusted repositories from git config; "." is the test's repository
+		content string
+		wantErr string
+		check   func(t *testing.T, cfg Config)
+	}{
+		{
+			name:    "settings",
+			content: "pathTemplate = \"/wt/{{.BranchSlug}}\"\nstayAfterEditor = true\ncopy = [\".env\"]\n[keys]\ndelete = [\"x\", \"delete\"]\n",
+			check: func(t *testing.T, cfg Config) {
+				if cfg.PathTemplate != "/wt/{{.BranchSlug}}" || !cfg.StayAfterEditor || !reflect.DeepEqual(cfg.Copy, []string{".env"}) {
+					t.Errorf("got %+v", cfg)
+				}
+				if got := cfg.Keys.Delete.Keys(); !reflect.DeepEqual(got, []string{"x", "delete"}) {
+					t.Errorf("delete keys = %q", got)
+				}
+			},
+		},
+		{name: "unknown key", content: "edtor = \"vim\"\n", wantErr: `unknown key "edtor" (did you mean "editor"?)`},
+		{name: "unknown table reported once", content: "[colours]\na = 1\nb = 2\n", wantErr: `config.toml: unknown key "colours"`},
+		{name: "syntax error", content: "editor = \n", wantErr: "line 1"},
+		{name: "invalid path template", content: "pathTemplate = \"{{.Nope\"\n", wantErr: "pathTemplate"},
+		{name: "invalid copy pattern", content: "copy = [\"../x\"]\n", wantErr: "copy"},
+		{name: "invalid action mode", content: "[action.x]\ncommand = \"echo\"\nmode = \"sideways\"\n", wantErr: "action.x.mode"},
+		{
+			name:    "untrusted repository file skips commands",
+			repo:    true,
+			content: "pathTemplate = \"/wt/{{.BranchSlug}}\"\neditor = \"evil\"\npostCreate = [\"curl x | sh\"]\n[action.x]\ncommand = \"evil\"\n",
+			check: func(t *testing.T, cfg Config) {
+				if cfg.PathTemplate != "/wt/{{.BranchSlug}}" {
+					t.Errorf("pathTemplate = %q, want it applied", cfg.PathTemplate)
+				}
+				if cfg.Editor != "" || cfg.PostCreate != nil {
+					t.Errorf("editor = %q, postCreate = %q; want them skipped", cfg.Editor, cfg.PostCreate)
+				}
+				for _, a := range cfg.Actions {
+					if a.Name == "x" {
+						t.Errorf("action x applied from an untrusted file")
+					}
+				}
+				if want := []string{"editor", "postCreate", "action.x"}; !reflect.DeepEqual(cfg.Untrusted, want) {
+					t.Errorf("Untrusted = %q, want %q", cfg.Untrusted, want)
+				}
+			},
+		},
+		{
+			name:    "trusted repository file sets commands",
+			repo:    true,
+			trusted: []string{"."},
+			content: "editor = \"code\"\npostCreate = [\"npm ci\"]\n",
+			check: func(t *testing.T, cfg Config) {
+				if cfg.Editor != "code" || !reflect.DeepEqual(cfg.PostCreate, []string{"npm ci"}) || cfg.Untrusted != nil {
+					t.Errorf("got editor %q, postCreate %q, untrusted %q", cfg.Editor, cfg.PostCreate, cfg.Untrusted)
+				}
+			},
+		},
+		{name: "repository file can't trust itself", repo: true, content: "trustedRepos = [\"/\"]\n", wantErr: "trustedRepos"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "config.toml")
+			if tt.repo {
+				path = filepath.Join(dir, RepoFileName)
+			}
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			var trusted []string
+			for _, p := range tt.trusted {
+				trusted = append(trusted, filepath.Join(dir, p))
+			}
+			cfg := Default()
+			err := loadFile(&cfg, path, trusted)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("loadFile() error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.check(t, cfg)
+		})
+	}
+}
@@ -0,0 +1,137 @@
+// Package editor builds commands that open a path in the user's editor.
+package editor
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	"github.com/fredrikmwold/git-worktree-tui/internal/shellwords"
+)
+
+// Command constructs an *exec.Cmd to open the given path in the user's editor.
+// tmpl is an optional command template such as "code --wait {{.Path}}"; when
+// empty, $VISUAL and then $EDITOR are used. Either way the command is split
+// into shell words, so values like "code --wait" or "nvim -p" work, and the
+// path is appended unless the command mentions {{.Path}}; {{.Name}} is the folder name.
+// The command is wired to the current terminal.
+func Command(tmpl, path string) (*exec.Cmd, error) {
+	source := "editor template"
+	line := tmpl
+	if line == "" {
+		source, line = "$VISUAL", os.Getenv("VISUAL")
+	}
+	if line == "" {
+		source, line = "$EDITOR", os.Getenv("EDITOR")
+	}
+	if line == "" {
+		return nil, fmt.Errorf("$EDITOR not set")
+	}
+	args, err := expand(line, path)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", source, err)
+	}
+	if len(args) == 0 {
+		return nil, fmt.Errorf("%s is empty", source)
+	}
+	bin, err := exec.LookPath(args[0])
+	if err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return nil, fmt.Errorf("editor %q (from %s) not found on PATH", args[0], source)
+		}
+		return nil, fmt.Errorf("editor %q (from %s): %w", args[0], source, err)
+	}
+	cmd := exec.Command(bin, args[1:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd, nil
+}
+
+// Validate checks that tmpl is a well-formed editor command template.
+func Validate(tmpl string) error {
+	_, err := expand(tmpl, "")
+	return err
+}
+
+// expand splits line into words and renders each as a template with {{.Path}}
+// and {{.Name}} (the folder name). If no word references the path, it is
+// appended as the last argument.
+func expand(line, path string) ([]string, error) {
+	words, err := shellwords.Split(line)
+	if err != nil {
+		return nil, err
+	}
+	vars := struct{ Path, Name string }{path, filepath.Base(path)}
+	args := make([]string, 0, len(words)+1)
+	usesPath := false
+	for _, w := range words {
+		if !strings.Contains(w, "{{") {
+			args = append(args, w)
+			continue
+		}
+		t, err := template.New("editor").Option("missingkey=error").Parse(w)
+		if err != nil {
+			return nil, err
+		}
+		var b bytes.Buffer
+		if err := t.Execute(&b, vars); err != nil {
+			return nil, err
+		}
+		usesPath = usesPath || refersToPath(t.Root)
+		args = append(args, b.String())
+	}
+	if !usesPath {
+		args = append(args, path)
+	}
+	return args, nil
+}
+
+// refersToPath reports whether the template tree under n mentions .Path (or $.Path).
+func refersToPath(n parse.Node) bool {
+	switch n := n.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return false
+		}
+		for _, c := range n.Nodes {
+			if refersToPath(c) {
+				return true
+			}
+		}
+	case *parse.ActionNode:
+		return refersToPath(n.Pipe)
+	case *parse.PipeNode:
+		if n == nil {
+			return false
+		}
+		for _, c := range n.Cmds {
+			if refersToPath(c) {
+				return true
+			}
+		}
+	case *parse.CommandNode:
+		for _, a := range n.Args {
+			if refersToPath(a) {
+				return true
+			}
+		}
+	case *parse.FieldNode:
+		return len(n.Ident) > 0 && n.Ident[0] == "Path"
+	case *parse.VariableNode:
+		return len(n.Ident) > 1 && n.Ident[0] == "$" && n.Ident[1] == "Path"
+	case *parse.IfNode:
+		return refersToPath(n.Pipe) || refersToPath(n.List) || refersToPath(n.ElseList)
+	case *parse.WithNode:
+		return refersToPath(n.Pipe) || refersToPath(n.List) || refersToPath(n.ElseList)
+	case *parse.RangeNode:
+		return refersToPath(n.Pipe) || refersToPath(n.List) || refersToPath(n.ElseList)
+	}
+	return false
+}
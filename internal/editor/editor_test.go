@@ -0,0 +1,46 @@
+package editor
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExpand(t *testing.T) {
+	const path = "/src/app-feature"
+	tests := []struct {
+		line    string
+		want    []string
+		wantErr bool
+	}{
+		{line: "nvim", want: []string{"nvim", path}},
+		{line: "code --wait", want: []string{"code", "--wait", path}},
+		{line: `"/opt/My Editor/bin/edit" -n`, want: []string{"/opt/My Editor/bin/edit", "-n", path}},
+		{line: "code --new-window {{.Path}}", want: []string{"code", "--new-window", path}},
+		{line: "code --folder-uri=file://{{.Path}}", want: []string{"code", "--folder-uri=file://" + path}},
+		{line: "idea {{$.Path}}", want: []string{"idea", path}},
+		{line: `sh -c "{{if .Path}}cd {{.Path}}{{end}}"`, want: []string{"sh", "-c", "cd " + path}},
+		// Templates that don't mention the path still get it appended
+		{line: "code --profile {{.Name}}", want: []string{"code", "--profile", "app-feature", path}},
+		{line: `code --title '{{printf "%s-%s" "a" "b"}}'`, want: []string{"code", "--title", "a-b", path}},
+		{line: "", want: []string{path}},
+		{line: "code {{.Nope}}", wantErr: true},
+		{line: "code {{.Path", wantErr: true},
+		{line: `code "unterminated`, wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := expand(tt.line, path)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("expand(%q) = %q, want an error", tt.line, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("expand(%q) error = %v", tt.line, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("expand(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
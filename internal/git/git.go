@@ -7,24 +7,54 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // Worktree represents a git worktree info we display
 // Path is absolute or relative as returned by git
 // Branch is the associated branch (if any)
-// IsBare indicates it's the main working tree (no .git/worktrees path)
-// Detected via parsing `git worktree list --porcelain`.
+// IsMain marks the main working tree, which git always lists first
+// The remaining flags mirror the optional lines of `git worktree list --porcelain`.
 
 type Worktree struct {
-	Path   string
-	Branch string
-	HEAD   string
-	IsMain bool
+	Path       string
+	Branch     string
+	HEAD       string
+	IsMain     bool
+	IsBare     bool
+	IsDetached bool
+	// Locked worktrees cannot be pruned, moved or removed without --force twice.
+	Locked     bool
+	LockReason string
+	// Prunable worktrees have lost their working directory and can be pruned.
+	Prunable    bool
+	PruneReason string
+}
+
+// BranchName returns the worktree's branch without the refs/heads/ prefix,
+// or "" for detached and bare worktrees.
+func (w Worktree) BranchName() string {
+	return strings.TrimPrefix(w.Branch, "refs/heads/")
+}
+
+// ShortHash abbreviates a commit hash for display.
+func ShortHash(h string) string {
+	if len(h) > 7 {
+		return h[:7]
+	}
+	return h
 }
 
 func runGit(args ...string) (string, error) {
+	return runGitIn("", args...)
+}
+
+// runGitIn runs git with dir as its working directory (the current directory if empty).
+func runGitIn(dir string, args ...string) (string, error) {
 	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
 	out, err := cmd.Output()
 	if err != nil {
 		var ee *exec.ExitError
@@ -42,44 +72,212 @@ func ListWorktrees() ([]Worktree, error) {
 	if err != nil {
 		return nil, err
 	}
+	return parseWorktrees(out), nil
+}
+
+// parseWorktrees parses the output of `git worktree list --porcelain`.
+// Records are separated by blank lines; each starts with a 'worktree <path>' line.
+func parseWorktrees(out string) []Worktree {
 	var wts []Worktree
 	s := bufio.NewScanner(strings.NewReader(out))
 	wt := Worktree{}
 	inBlock := false
 	for s.Scan() {
 		line := s.Text()
-		if strings.HasPrefix(line, "worktree ") {
+		attr, val, _ := strings.Cut(line, " ")
+		switch attr {
+		case "worktree":
 			if inBlock {
 				wts = append(wts, wt)
 			}
 			inBlock = true
-			wt = Worktree{}
-			wt.Path = strings.TrimSpace(strings.TrimPrefix(line, "worktree "))
-			// main tree has no 'worktree' subdir under .git/worktrees
-			wt.IsMain = true // default; we'll refine below if we see 'worktree <path>' and 'bare' or other markers
-			continue
+			wt = Worktree{Path: strings.TrimSpace(val)}
+		case "branch":
+			wt.Branch = strings.TrimSpace(val)
+		case "HEAD":
+			wt.HEAD = strings.TrimSpace(val)
+		case "bare":
+			wt.IsBare = true
+		case "detached":
+			wt.IsDetached = true
+		case "locked":
+			wt.Locked = true
+			wt.LockReason = unquoteReason(val)
+		case "prunable":
+			wt.Prunable = true
+			wt.PruneReason = unquoteReason(val)
 		}
-		if strings.HasPrefix(line, "branch ") {
-			wt.Branch = strings.TrimSpace(strings.TrimPrefix(line, "branch "))
-			continue
-		}
-		if strings.HasPrefix(line, "HEAD ") {
-			wt.HEAD = strings.TrimSpace(strings.TrimPrefix(line, "HEAD "))
-			continue
-		}
-		// ignore other lines like 'bare', 'detached', etc.
 	}
 	if inBlock {
 		wts = append(wts, wt)
 	}
-	// Determine main vs secondary: the first entry from git is usually main.
+	// git always lists the main worktree first
 	if len(wts) > 0 {
 		wts[0].IsMain = true
-		for i := 1; i < len(wts); i++ {
-			wts[i].IsMain = false
+	}
+	return wts
+}
+
+// unquoteReason decodes a lock/prune reason. Git C-quotes reasons that
+// contain newlines or other special characters.
+func unquoteReason(s string) string {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, `"`) {
+		if u, err := strconv.Unquote(s); err == nil {
+			return u
+		}
+	}
+	return s
+}
+
+// Status summarizes the working tree state of a worktree and how its
+// branch compares to the upstream.
+type Status struct {
+	Staged     int
+	Unstaged   int
+	Untracked  int
+	Conflicted int
+	// Upstream is the tracking branch (e.g. "origin/main"); empty if none.
+	Upstream string
+	Ahead    int
+	Behind   int
+}
+
+// Dirty reports whether the worktree has any uncommitted changes.
+func (s Status) Dirty() bool {
+	return s.Staged+s.Unstaged+s.Untracked+s.Conflicted > 0
+}
+
+// WorktreeStatus returns the status of the worktree at path.
+// Equivalent to: git -C <path> status --porcelain=v2 --branch
+func WorktreeStatus(path string) (Status, error) {
+	if path == "" {
+		return Status{}, fmt.Errorf("path required")
+	}
+	out, err := runGitIn(path, "status", "--porcelain=v2", "--branch")
+	if err != nil {
+		return Status{}, err
+	}
+	return parseStatus(out), nil
+}
+
+// ChangedFiles lists uncommitted changes in the worktree at path in
+// `git status --short` form (e.g. " M main.go", "?? notes.txt"), including
+// every untracked file. These are the changes a forced removal would discard.
+func ChangedFiles(path string) ([]string, error) {
+	if path == "" {
+		return nil, fmt.Errorf("path required")
+	}
+	out, err := runGitIn(path, "status", "--porcelain", "--untracked-files=all")
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, l := range strings.Split(out, "\n") {
+		if strings.TrimSpace(l) == "" {
+			continue
 		}
+		files = append(files, l)
 	}
-	return wts, nil
+	return files, nil
+}
+
+// Commit is a commit in a branch's history.
+type Commit struct {
+	Hash    string // abbreviated
+	Subject string
+	Author  string
+	When    time.Time // committer date
+}
+
+// RecentCommits returns up to n commits of HEAD in the worktree at path, newest
+// first; none on an unborn branch.
+// Equivalent to: git -C <path> log -n <n> --format=%h%x00%s%x00%an%x00%ct
+func RecentCommits(path string, n int) ([]Commit, error) {
+	if path == "" {
+		return nil, fmt.Errorf("path required")
+	}
+	if _, err := runGitIn(path, "rev-parse", "--verify", "--quiet", "HEAD"); err != nil {
+		// An unborn branch (no commits yet) has no history, which git log treats as an error
+		if _, serr := runGitIn(path, "symbolic-ref", "--quiet", "HEAD"); serr == nil {
+			return nil, nil
+		}
+	}
+	out, err := runGitIn(path, "log", "-n", strconv.Itoa(n), "--format=%h%x00%s%x00%an%x00%ct")
+	if err != nil {
+		return nil, err
+	}
+	var commits []Commit
+	for _, l := range strings.Split(strings.TrimSpace(out), "\n") {
+		f := strings.Split(l, "\x00")
+		if len(f) != 4 {
+			continue
+		}
+		secs, _ := strconv.ParseInt(f[3], 10, 64)
+		commits = append(commits, Commit{Hash: f[0], Subject: f[1], Author: f[2], When: time.Unix(secs, 0)})
+	}
+	return commits, nil
+}
+
+// LastChanged returns the newest modification time among the uncommitted
+// files (including untracked ones) of the worktree at path; zero when clean.
+func LastChanged(path string) (time.Time, error) {
+	if path == "" {
+		return time.Time{}, fmt.Errorf("path required")
+	}
+	out, err := runGitIn(path, "status", "--porcelain", "-z", "--untracked-files=all")
+	if err != nil {
+		return time.Time{}, err
+	}
+	var last time.Time
+	fields := strings.Split(out, "\x00")
+	for i := 0; i < len(fields); i++ {
+		e := fields[i]
+		if len(e) < 4 {
+			continue
+		}
+		if e[0] == 'R' || e[0] == 'C' {
+			// Renames and copies are followed by their original path
+			i++
+		}
+		// Deleted files have nothing to stat
+		if fi, err := os.Lstat(filepath.Join(path, e[3:])); err == nil && fi.ModTime().After(last) {
+			last = fi.ModTime()
+		}
+	}
+	return last, nil
+}
+
+// parseStatus parses `git status --porcelain=v2 --branch` output.
+func parseStatus(out string) Status {
+	var st Status
+	s := bufio.NewScanner(strings.NewReader(out))
+	for s.Scan() {
+		line := s.Text()
+		switch {
+		case strings.HasPrefix(line, "# branch.upstream "):
+			st.Upstream = strings.TrimPrefix(line, "# branch.upstream ")
+		case strings.HasPrefix(line, "# branch.ab "):
+			// Format: "# branch.ab +<ahead> -<behind>"
+			fmt.Sscanf(strings.TrimPrefix(line, "# branch.ab "), "+%d -%d", &st.Ahead, &st.Behind)
+		case strings.HasPrefix(line, "1 "), strings.HasPrefix(line, "2 "):
+			// Ordinary or renamed entry: "<1|2> <XY> ..." where '.' means unchanged
+			if len(line) < 4 {
+				continue
+			}
+			if line[2] != '.' {
+				st.Staged++
+			}
+			if line[3] != '.' {
+				st.Unstaged++
+			}
+		case strings.HasPrefix(line, "u "):
+			st.Conflicted++
+		case strings.HasPrefix(line, "? "):
+			st.Untracked++
+		}
+	}
+	return st
 }
 
 // ListBranches returns local branches without the leading '*'
@@ -111,8 +309,10 @@ type Branch struct {
 	Upstream string
 }
 
-// ListBranchesDetailed returns local branches only (most recent first),
-// including their upstream (tracking) info when available.
+// ListBranchesDetailed returns local branches (most recent first), including their
+// upstream (tracking) info when available, followed by remote-only branches.
+// Remote branches that a local branch already tracks, or that share a local
+// branch's name, are left out since picking the local one is equivalent.
 func ListBranchesDetailed() ([]Branch, error) {
 	var locals []Branch
 
@@ -122,6 +322,8 @@ func ListBranchesDetailed() ([]Branch, error) {
 	if err != nil {
 		return nil, err
 	}
+	localNames := map[string]bool{}
+	tracked := map[string]bool{}
 	for _, l := range strings.Split(strings.TrimSpace(outLocal), "\n") {
 		if strings.TrimSpace(l) == "" {
 			continue
@@ -133,11 +335,168 @@ func ListBranchesDetailed() ([]Branch, error) {
 			upstream = strings.TrimSpace(l[tab+1:])
 		}
 		locals = append(locals, Branch{Name: name, Upstream: upstream})
+		localNames[name] = true
+		if upstream != "" {
+			tracked[upstream] = true
+		}
+	}
+
+	remotes, err := ListRemotes()
+	if err != nil {
+		return nil, err
+	}
+	if len(remotes) == 0 {
+		return locals, nil
+	}
+	// Remote branches, same ordering; full refname lets us split "<remote>/<branch>"
+	// reliably and skip symbolic refs like refs/remotes/origin/HEAD
+	outRemote, err := runGit("for-each-ref", "--sort=-committerdate", "--format=%(refname)\t%(symref)", "refs/remotes")
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range strings.Split(strings.TrimSpace(outRemote), "\n") {
+		ref, symref, _ := strings.Cut(l, "\t")
+		if ref == "" || symref != "" {
+			continue
+		}
+		short := strings.TrimPrefix(ref, "refs/remotes/")
+		remote := matchRemote(short, remotes)
+		if remote == "" {
+			continue
+		}
+		name := strings.TrimPrefix(short, remote+"/")
+		if name == "HEAD" || tracked[short] || localNames[name] {
+			continue
+		}
+		locals = append(locals, Branch{Name: name, IsRemote: true, Remote: remote, RemoteRef: short})
 	}
 	return locals, nil
 }
 
+// ListRemotes returns the names of configured remotes.
+func ListRemotes() ([]string, error) {
+	out, err := runGit("remote")
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(out), nil
+}
+
+// matchRemote returns the longest remote name that prefixes the short
+// remote-tracking ref ("<remote>/<branch>"), or "" if none does.
+func matchRemote(short string, remotes []string) string {
+	best := ""
+	for _, r := range remotes {
+		if strings.HasPrefix(short, r+"/") && len(r) > len(best) {
+			best = r
+		}
+	}
+	return best
+}
+
+// RefKind classifies a Ref.
+type RefKind int
+
+const (
+	RefBranch RefKind = iota
+	RefRemote
+	RefTag
+)
+
+// Ref is a named commit-ish a new branch can start from.
+type Ref struct {
+	Name    string // short name, e.g. "main", "origin/main" or "v1.2.0"
+	Kind    RefKind
+	Subject string // subject line of the commit (or tag message) it points to
+}
+
+// ListRefs returns local branches, remote branches and tags, each group
+// ordered by most recent committer date. Symbolic refs such as origin/HEAD are skipped.
+func ListRefs() ([]Ref, error) {
+	out, err := runGit("for-each-ref", "--sort=-committerdate", "--format=%(refname)\t%(refname:short)\t%(symref)\t%(contents:subject)", "refs/heads", "refs/remotes", "refs/tags")
+	if err != nil {
+		return nil, err
+	}
+	var branches, remotes, tags []Ref
+	for _, l := range strings.Split(strings.TrimSpace(out), "\n") {
+		f := strings.SplitN(l, "\t", 4)
+		if len(f) < 4 || f[2] != "" {
+			continue
+		}
+		r := Ref{Name: f[1], Subject: f[3]}
+		switch {
+		case strings.HasPrefix(f[0], "refs/heads/"):
+			r.Kind = RefBranch
+			branches = append(branches, r)
+		case strings.HasPrefix(f[0], "refs/remotes/"):
+			r.Kind = RefRemote
+			remotes = append(remotes, r)
+		case strings.HasPrefix(f[0], "refs/tags/"):
+			r.Kind = RefTag
+			tags = append(tags, r)
+		}
+	}
+	return append(append(branches, remotes...), tags...), nil
+}
+
+// FetchAll fetches all remotes and prunes deleted remote branches, calling
+// progress with each progress line git reports while it runs. Git and ssh
+// never prompt for credentials, so a remote needing them fails instead.
+// Equivalent to: git fetch --all --prune --progress
+func FetchAll(progress func(line string)) error {
+	args := []string{"fetch", "--all", "--prune", "--progress"}
+	cmd := exec.Command("git", args...)
+	cmd.Env = nonInteractiveEnv()
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	// Keep the last few lines so failures carry git's explanation
+	var tail []string
+	s := bufio.NewScanner(stderr)
+	s.Split(scanProgressLines)
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if line == "" {
+			continue
+		}
+		if progress != nil {
+			progress(line)
+		}
+		tail = append(tail, line)
+		if len(tail) > 5 {
+			tail = tail[1:]
+		}
+	}
+	if err := cmd.Wait(); err != nil {
+		return fmt.Errorf("git %v failed: %v\n%s", args, err, strings.Join(tail, "\n"))
+	}
+	return nil
+}
+
+// scanProgressLines is a bufio.SplitFunc like bufio.ScanLines that also treats
+// carriage returns as line breaks, since git redraws progress lines with '\r'.
+func scanProgressLines(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+	for i, b := range data {
+		if b == '\n' || b == '\r' {
+			return i + 1, data[:i], nil
+		}
+	}
+	if atEOF {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
+
 // CreateWorktreeFromRef creates a new branch from a given ref and adds a worktree.
+// When fromRef is a remote-tracking branch, git sets it as the new branch's upstream
+// (per the default branch.autoSetupMerge behavior).
 // Equivalent to: git worktree add -b <branch> <path> <fromRef>
 func CreateWorktreeFromRef(branch, targetDir, fromRef string) error {
 	if branch == "" || targetDir == "" || fromRef == "" {
@@ -183,17 +542,189 @@ func RemoveWorktree(path string, force bool) error {
 	return err
 }
 
-// DefaultWorktreeDir suggests a directory name for a branch under .worktrees/<branch>
-func DefaultWorktreeDir(branch string) string {
-	// Place new worktrees as siblings of the current repo directory
-	// Use the repo's base directory name and append the branch name
-	// e.g., /path/parent/repo-branch
-	cwd, err := os.Getwd()
+// ErrBranchNotMerged is returned by DeleteBranch when a non-forced delete
+// would lose commits that are not on the default branch.
+var ErrBranchNotMerged = errors.New("branch is not fully merged")
+
+// DefaultBranch returns the short name of the repository's default branch:
+// the target of origin/HEAD when set, otherwise main or master if present,
+// otherwise the branch checked out in the main worktree.
+func DefaultBranch() (string, error) {
+	if out, err := runGit("symbolic-ref", "--quiet", "--short", "refs/remotes/origin/HEAD"); err == nil {
+		if name := strings.TrimPrefix(strings.TrimSpace(out), "origin/"); name != "" {
+			return name, nil
+		}
+	}
+	for _, name := range []string{"main", "master"} {
+		if refExists("refs/heads/" + name) {
+			return name, nil
+		}
+	}
+	wts, err := ListWorktrees()
+	if err != nil {
+		return "", err
+	}
+	if len(wts) > 0 && wts[0].BranchName() != "" {
+		return wts[0].BranchName(), nil
+	}
+	return "", fmt.Errorf("could not determine default branch")
+}
+
+// DefaultStartPoint returns the ref new branches start from by default: the
+// default branch, or its origin counterpart when there is no local branch of
+// that name (e.g. origin/HEAD names main but only master was checked out).
+func DefaultStartPoint() (string, error) {
+	def, err := DefaultBranch()
+	if err != nil {
+		return "", err
+	}
+	if !refExists("refs/heads/"+def) && refExists("refs/remotes/origin/"+def) {
+		return "origin/" + def, nil
+	}
+	return def, nil
+}
+
+// CheckBranchName returns an error when name is not a valid new branch name.
+// Equivalent to: git check-ref-format --branch <name>
+func CheckBranchName(name string) error {
+	out, err := runGit("check-ref-format", "--branch", name)
+	// --branch also expands shorthands like @{-1}; only the literal name is wanted
+	if err != nil || strings.TrimSpace(out) != name {
+		return fmt.Errorf("%q is not a valid branch name", name)
+	}
+	return nil
+}
+
+// BranchExists reports whether a local branch with the given name exists.
+func BranchExists(name string) bool {
+	return refExists("refs/heads/" + name)
+}
+
+// refExists reports whether the fully qualified ref exists.
+func refExists(ref string) bool {
+	_, err := runGit("show-ref", "--verify", "--quiet", ref)
+	return err == nil
+}
+
+// IsMerged reports whether all commits of branch are reachable from into.
+func IsMerged(branch, into string) (bool, error) {
+	cmd := exec.Command("git", "merge-base", "--is-ancestor", branch, into)
+	err := cmd.Run()
+	if err == nil {
+		return true, nil
+	}
+	var ee *exec.ExitError
+	if errors.As(err, &ee) && ee.ExitCode() == 1 {
+		return false, nil
+	}
+	return false, fmt.Errorf("git merge-base --is-ancestor %s %s failed: %v", branch, into, err)
+}
+
+// DeleteBranch deletes a local branch. Unless force is true, it follows
+// `git branch -d` semantics but judged against the default branch (local or
+// its origin counterpart) rather than the current HEAD, returning
+// ErrBranchNotMerged when the branch has unmerged commits.
+// Equivalent to: git branch -D <branch> (after the merge check when not forced)
+func DeleteBranch(branch string, force bool) error {
+	if branch == "" {
+		return fmt.Errorf("branch required")
+	}
+	if !force {
+		def, err := DefaultBranch()
+		if err != nil {
+			return err
+		}
+		if branch == def {
+			return fmt.Errorf("refusing to delete default branch %s", def)
+		}
+		merged := false
+		for _, into := range []string{"refs/heads/" + def, "refs/remotes/origin/" + def} {
+			if !refExists(into) {
+				continue
+			}
+			ok, err := IsMerged("refs/heads/"+branch, into)
+			if err != nil {
+				return err
+			}
+			if ok {
+				merged = true
+				break
+			}
+		}
+		if !merged {
+			return fmt.Errorf("%w into %s", ErrBranchNotMerged, def)
+		}
+	}
+	_, err := runGit("branch", "-D", branch)
+	return err
+}
+
+// ConfigGet returns the value of a git config key, or "" when it is unset.
+func ConfigGet(key string) (string, error) {
+	vals, err := ConfigGetAll(key)
+	if err != nil || len(vals) == 0 {
+		return "", err
+	}
+	return vals[len(vals)-1], nil
+}
+
+// nonInteractiveEnv returns the environment for git commands that run while the
+// TUI owns the terminal: a password or passphrase prompt on /dev/tty would fight
+// it for input and look like a hang. ssh runs in batch mode unless the user
+// chose their own ssh command, which GIT_SSH_COMMAND would override.
+func nonInteractiveEnv() []string {
+	env := append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	if os.Getenv("GIT_SSH_COMMAND") != "" || os.Getenv("GIT_SSH") != "" {
+		return env
+	}
+	if c, err := ConfigGet("core.sshCommand"); err == nil && c != "" {
+		return env
+	}
+	return append(env, "GIT_SSH_COMMAND=ssh -o BatchMode=yes")
+}
+
+// ConfigGetAll returns every value of a multi-valued git config key, in the
+// order git reads them (system, global, then repository config).
+func ConfigGetAll(key string) ([]string, error) {
+	cmd := exec.Command("git", "config", "--get-all", key)
+	out, err := cmd.Output()
+	if err != nil {
+		var ee *exec.ExitError
+		// Exit code 1 means the key is not set
+		if errors.As(err, &ee) && ee.ExitCode() == 1 {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("git config --get-all %s failed: %v", key, err)
+	}
+	return strings.Split(strings.TrimRight(string(out), "\n"), "\n"), nil
+}
+
+// ConfigEntry is a key/value pair from git config.
+type ConfigEntry struct {
+	Key   string
+	Value string
+}
+
+// ConfigGetRegexp returns all git config entries whose key matches pattern,
+// in the order git reads them.
+func ConfigGetRegexp(pattern string) ([]ConfigEntry, error) {
+	cmd := exec.Command("git", "config", "-z", "--get-regexp", pattern)
+	out, err := cmd.Output()
 	if err != nil {
-		return filepath.Join(".worktrees", branch)
+		var ee *exec.ExitError
+		if errors.As(err, &ee) && ee.ExitCode() == 1 {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("git config --get-regexp %s failed: %v", pattern, err)
+	}
+	// With -z each entry is "<key>\n<value>\x00"
+	var entries []ConfigEntry
+	for _, rec := range strings.Split(string(out), "\x00") {
+		if rec == "" {
+			continue
+		}
+		k, v, _ := strings.Cut(rec, "\n")
+		entries = append(entries, ConfigEntry{Key: k, Value: v})
 	}
-	parent := filepath.Dir(cwd)
-	base := filepath.Base(cwd)
-	name := base + "-" + branch
-	return filepath.Join(parent, name)
+	return entries, nil
 }
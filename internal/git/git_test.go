@@ -0,0 +1,446 @@
+package git
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseWorktrees(t *testing.T) {
+	tests := []struct {
+		name string
+		out  string
+		want []Worktree
+	}{
+		{
+			name: "empty",
+			out:  "",
+			want: nil,
+		},
+		{
+			name: "main and branch",
+			out: "worktree /src/app\nHEAD 1111111111111111111111111111111111111111\nbranch refs/heads/main\n\n" +
+				"worktree /src/app-feature\nHEAD 2222222222222222222222222222222222222222\nbranch refs/heads/feature/x\n",
+			want: []Worktree{
+				{Path: "/src/app", HEAD: "1111111111111111111111111111111111111111", Branch: "refs/heads/main", IsMain: true},
+				{Path: "/src/app-feature", HEAD: "2222222222222222222222222222222222222222", Branch: "refs/heads/feature/x"},
+			},
+		},
+		{
+			name: "bare and detached",
+			out:  "worktree /src/app.git\nbare\n\nworktree /src/app-hotfix\nHEAD 3333333333333333333333333333333333333333\ndetached\n\n",
+			want: []Worktree{
+				{Path: "/src/app.git", IsMain: true, IsBare: true},
+				{Path: "/src/app-hotfix", HEAD: "3333333333333333333333333333333333333333", IsDetached: true},
+			},
+		},
+		{
+			name: "locked and prunable",
+			out: "worktree /src/app\nbranch refs/heads/main\n\n" +
+				"worktree /mnt/usb/app-a\nbranch refs/heads/a\nlocked\n\n" +
+				"worktree /mnt/usb/app-b\nbranch refs/heads/b\nlocked on usb stick\n\n" +
+				"worktree /tmp/app-c\nbranch refs/heads/c\nprunable gitdir file points to non-existent location\n",
+			want: []Worktree{
+				{Path: "/src/app", Branch: "refs/heads/main", IsMain: true},
+				{Path: "/mnt/usb/app-a", Branch: "refs/heads/a", Locked: true},
+				{Path: "/mnt/usb/app-b", Branch: "refs/heads/b", Locked: true, LockReason: "on usb stick"},
+				{Path: "/tmp/app-c", Branch: "refs/heads/c", Prunable: true, PruneReason: "gitdir file points to non-existent location"},
+			},
+		},
+		{
+			name: "path with spaces",
+			out:  "worktree /src/my app\nbranch refs/heads/main\n",
+			want: []Worktree{{Path: "/src/my app", Branch: "refs/heads/main", IsMain: true}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseWorktrees(tt.out); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseWorktrees() =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnquoteReason(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"", ""},
+		{"on usb stick", "on usb stick"},
+		{"  padded  ", "padded"},
+		{`"two\nlines"`, "two\nlines"},
+		{`"tab\there"`, "tab\there"},
+		{`"unterminated`, `"unterminated`},
+	}
+	for _, tt := range tests {
+		if got := unquoteReason(tt.in); got != tt.want {
+			t.Errorf("unquoteReason(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseStatus(t *testing.T) {
+	tests := []struct {
+		name string
+		out  string
+		want Status
+	}{
+		{
+			name: "clean without upstream",
+			out:  "# branch.oid 1111111111111111111111111111111111111111\n# branch.head main\n",
+			want: Status{},
+		},
+		{
+			name: "ahead and behind",
+			out:  "# branch.oid 1111111111111111111111111111111111111111\n# branch.head main\n# branch.upstream origin/main\n# branch.ab +2 -3\n",
+			want: Status{Upstream: "origin/main", Ahead: 2, Behind: 3},
+		},
+		{
+			name: "staged, unstaged and both",
+			out: "# branch.head main\n" +
+				"1 M. N... 100644 100644 100644 aaa bbb staged.go\n" +
+				"1 .M N... 100644 100644 100644 aaa bbb unstaged.go\n" +
+				"1 MM N... 100644 100644 100644 aaa bbb both.go\n",
+			want: Status{Staged: 2, Unstaged: 2},
+		},
+		{
+			name: "rename, conflict and untracked",
+			out: "# branch.head main\n" +
+				"2 R. N... 100644 100644 100644 aaa bbb R100 new.go\told.go\n" +
+				"u UU N... 100644 100644 100644 100644 aaa bbb ccc conflict.go\n" +
+				"? notes.txt\n? tmp/scratch.txt\n",
+			want: Status{Staged: 1, Conflicted: 1, Untracked: 2},
+		},
+		{
+			name: "ignored entries are not counted",
+			out:  "# branch.head main\n! build/out\n",
+			want: Status{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseStatus(tt.out); got != tt.want {
+				t.Errorf("parseStatus() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// testRepo creates an empty repository isolated from the user's git config.
+func testRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_AUTHOR_NAME", "Test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+	dir := t.TempDir()
+	gitIn(t, dir, "init", "-q", "--initial-branch=main")
+	return dir
+}
+
+// chdir changes into dir for the rest of the test, for functions that run git
+// in the current directory.
+func chdir(t *testing.T, dir string) {
+	t.Helper()
+	old, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(old) })
+}
+
+// commitOn creates branch from the current HEAD (unless it is the current
+// branch), adds an empty commit to it and switches back to main.
+func commitOn(t *testing.T, dir, branch, msg string) {
+	t.Helper()
+	if branch != "main" {
+		gitIn(t, dir, "checkout", "-q", "-B", branch)
+	}
+	gitIn(t, dir, "commit", "-q", "--allow-empty", "-m", msg)
+	gitIn(t, dir, "checkout", "-q", "main")
+}
+
+// testRemote adds a bare repository as origin of dir, pushes main to it and
+// points origin/HEAD at it, returning the remote's path.
+func testRemote(t *testing.T, dir string) string {
+	t.Helper()
+	remote := filepath.Join(t.TempDir(), "origin.git")
+	gitIn(t, dir, "init", "-q", "--bare", remote)
+	gitIn(t, dir, "remote", "add", "origin", remote)
+	gitIn(t, dir, "push", "-q", "origin", "main")
+	gitIn(t, dir, "remote", "set-head", "origin", "main")
+	return remote
+}
+
+func TestDeleteBranch(t *testing.T) {
+	dir := testRepo(t)
+	chdir(t, dir)
+	commitOn(t, dir, "main", "initial")
+	testRemote(t, dir)
+	gitIn(t, dir, "branch", "merged")
+	// Merged upstream but not yet pulled into the local main
+	commitOn(t, dir, "merged-upstream", "merged upstream")
+	gitIn(t, dir, "push", "-q", "origin", "merged-upstream:main")
+	commitOn(t, dir, "unmerged", "unmerged work")
+	commitOn(t, dir, "unmerged-forced", "more unmerged work")
+
+	tests := []struct {
+		branch      string
+		force       bool
+		wantErr     string // "" for success
+		wantDeleted bool
+	}{
+		{branch: "merged", wantDeleted: true},
+		{branch: "merged-upstream", wantDeleted: true},
+		{branch: "unmerged", wantErr: ErrBranchNotMerged.Error()},
+		{branch: "unmerged-forced", force: true, wantDeleted: true},
+		{branch: "main", wantErr: "refusing to delete default branch main"},
+		{branch: "", wantErr: "branch required"},
+	}
+	for _, tt := range tests {
+		err := DeleteBranch(tt.branch, tt.force)
+		switch {
+		case tt.wantErr == "" && err != nil:
+			t.Errorf("DeleteBranch(%q, %v) error = %v", tt.branch, tt.force, err)
+		case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+			t.Errorf("DeleteBranch(%q, %v) error = %v, want one containing %q", tt.branch, tt.force, err, tt.wantErr)
+		}
+		if tt.branch == "unmerged" && !errors.Is(err, ErrBranchNotMerged) {
+			t.Errorf("DeleteBranch(%q) error = %v, want ErrBranchNotMerged", tt.branch, err)
+		}
+		if tt.branch != "" && BranchExists(tt.branch) == tt.wantDeleted {
+			t.Errorf("after DeleteBranch(%q, %v), branch exists = %v", tt.branch, tt.force, !tt.wantDeleted)
+		}
+	}
+}
+
+func gitIn(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	if _, err := runGitIn(dir, args...); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// writeFile writes path below dir with the given modification time.
+func writeFile(t *testing.T, dir, path string, mtime time.Time) {
+	t.Helper()
+	p := filepath.Join(dir, path)
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(p, []byte(path), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(p, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRecentCommits(t *testing.T) {
+	dir := testRepo(t)
+	commits, err := RecentCommits(dir, 10)
+	if err != nil || commits != nil {
+		t.Fatalf("RecentCommits() on an unborn branch = %v, %v; want no commits and no error", commits, err)
+	}
+	for _, msg := range []string{"first", "second", "third"} {
+		gitIn(t, dir, "commit", "-q", "--allow-empty", "-m", msg)
+	}
+	tests := []struct {
+		n    int
+		want []string
+	}{
+		{1, []string{"third"}},
+		{2, []string{"third", "second"}},
+		{10, []string{"third", "second", "first"}},
+	}
+	for _, tt := range tests {
+		commits, err := RecentCommits(dir, tt.n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, c := range commits {
+			got = append(got, c.Subject)
+			if c.Hash == "" || c.Author != "Test" || c.When.IsZero() {
+				t.Errorf("RecentCommits(%d) returned %+v", tt.n, c)
+			}
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("RecentCommits(%d) subjects = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestLastChanged(t *testing.T) {
+	base := time.Now().Add(-24 * time.Hour).Truncate(time.Second)
+	older, newer := base.Add(time.Hour), base.Add(2*time.Hour)
+	tests := []struct {
+		name  string
+		setup func(t *testing.T, dir string)
+		want  time.Time
+	}{
+		{
+			name:  "clean",
+			setup: func(t *testing.T, dir string) {},
+		},
+		{
+			name: "modified and untracked",
+			setup: func(t *testing.T, dir string) {
+				writeFile(t, dir, "abcd.txt", older)
+				writeFile(t, dir, "sub/dir/new.txt", newer)
+			},
+			want: newer,
+		},
+		{
+			name: "deleted only",
+			setup: func(t *testing.T, dir string) {
+				if err := os.Remove(filepath.Join(dir, "abcd.txt")); err != nil {
+					t.Fatal(err)
+				}
+			},
+		},
+		{
+			// The original path of a rename is not an entry of its own: read as
+			// one, "abcd.txt" would look like the unchanged but newer "d.txt"
+			name: "rename",
+			setup: func(t *testing.T, dir string) {
+				gitIn(t, dir, "mv", "abcd.txt", "moved.txt")
+				if err := os.Chtimes(filepath.Join(dir, "moved.txt"), older, older); err != nil {
+					t.Fatal(err)
+				}
+			},
+			want: older,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := testRepo(t)
+			writeFile(t, dir, "abcd.txt", base)
+			writeFile(t, dir, "d.txt", base)
+			gitIn(t, dir, "add", ".")
+			gitIn(t, dir, "commit", "-q", "-m", "init")
+			// Touch an unchanged file after the commit; it stays clean
+			if err := os.Chtimes(filepath.Join(dir, "d.txt"), newer, newer); err != nil {
+				t.Fatal(err)
+			}
+			tt.setup(t, dir)
+			got, err := LastChanged(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("LastChanged() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNonInteractiveEnv(t *testing.T) {
+	tests := []struct {
+		name       string
+		sshCommand string // GIT_SSH_COMMAND set by the user
+		sshConfig  string // core.sshCommand in git config
+		want       string // resulting GIT_SSH_COMMAND
+	}{
+		{name: "batch mode by default", want: "ssh -o BatchMode=yes"},
+		{name: "user's GIT_SSH_COMMAND", sshCommand: "ssh -i ~/.ssh/work", want: "ssh -i ~/.ssh/work"},
+		{name: "user's core.sshCommand", sshConfig: "ssh -i ~/.ssh/work", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := testRepo(t)
+			t.Setenv("GIT_SSH", "")
+			t.Setenv("GIT_SSH_COMMAND", tt.sshCommand)
+			if tt.sshConfig != "" {
+				global := filepath.Join(dir, "gitconfig")
+				t.Setenv("GIT_CONFIG_GLOBAL", global)
+				gitIn(t, dir, "config", "--global", "core.sshCommand", tt.sshConfig)
+			}
+			env := map[string]string{}
+			for _, kv := range nonInteractiveEnv() {
+				k, v, _ := strings.Cut(kv, "=")
+				env[k] = v
+			}
+			if env["GIT_TERMINAL_PROMPT"] != "0" {
+				t.Errorf("GIT_TERMINAL_PROMPT = %q, want 0", env["GIT_TERMINAL_PROMPT"])
+			}
+			if env["GIT_SSH_COMMAND"] != tt.want {
+				t.Errorf("GIT_SSH_COMMAND = %q, want %q", env["GIT_SSH_COMMAND"], tt.want)
+			}
+		})
+	}
+}
+
+func TestListBranchesDetailed(t *testing.T) {
+	dir := testRepo(t)
+	chdir(t, dir)
+	commitOn(t, dir, "main", "initial")
+	testRemote(t, dir) // origin/main and the symbolic origin/HEAD
+	// A local branch tracking a remote branch of the same name
+	gitIn(t, dir, "branch", "tracked")
+	gitIn(t, dir, "push", "-q", "-u", "origin", "tracked")
+	// A local branch tracking a remote branch by another name
+	gitIn(t, dir, "branch", "mine")
+	gitIn(t, dir, "push", "-q", "-u", "origin", "mine:theirs")
+	// A local branch that shares a remote branch's name without tracking it
+	gitIn(t, dir, "branch", "same")
+	gitIn(t, dir, "push", "-q", "origin", "same")
+	// Remote-only branches, one with a slash in its name
+	gitIn(t, dir, "push", "-q", "origin", "main:feature/x")
+	// Remotes whose names prefix each other: the longest match wins
+	for _, r := range []string{"team", "team/fork"} {
+		bare := filepath.Join(t.TempDir(), "remote.git")
+		gitIn(t, dir, "init", "-q", "--bare", bare)
+		gitIn(t, dir, "remote", "add", r, bare)
+	}
+	gitIn(t, dir, "push", "-q", "team", "main:w")
+	gitIn(t, dir, "push", "-q", "team/fork", "main:z")
+	// Left behind by a remote that no longer exists
+	gitIn(t, dir, "update-ref", "refs/remotes/gone/old", "HEAD")
+
+	brs, err := ListBranchesDetailed()
+	if err != nil {
+		t.Fatal(err)
+	}
+	locals := map[string]string{}
+	remotes := map[string]Branch{}
+	for _, b := range brs {
+		if b.IsRemote {
+			remotes[b.RemoteRef] = b
+		} else {
+			locals[b.Name] = b.Upstream
+		}
+	}
+	wantLocals := map[string]string{"main": "", "tracked": "origin/tracked", "mine": "origin/theirs", "same": ""}
+	if !reflect.DeepEqual(locals, wantLocals) {
+		t.Errorf("local branches = %v, want %v", locals, wantLocals)
+	}
+	wantRemotes := map[string]Branch{
+		"origin/feature/x": {Name: "feature/x", IsRemote: true, Remote: "origin", RemoteRef: "origin/feature/x"},
+		"team/w":           {Name: "w", IsRemote: true, Remote: "team", RemoteRef: "team/w"},
+		"team/fork/z":      {Name: "z", IsRemote: true, Remote: "team/fork", RemoteRef: "team/fork/z"},
+	}
+	if !reflect.DeepEqual(remotes, wantRemotes) {
+		t.Errorf("remote branches =\n%+v\nwant\n%+v", remotes, wantRemotes)
+	}
+	// Locals come first
+	for i, b := range brs {
+		if b.IsRemote && i < len(wantLocals) {
+			t.Errorf("remote branch %s listed before the local branches", b.RemoteRef)
+		}
+	}
+}
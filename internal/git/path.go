@@ -0,0 +1,131 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// DefaultPathTemplate places worktrees next to the repository as <repo>-<branch slug>.
+const DefaultPathTemplate = "{{.RepoRoot}}/../{{.Repo}}-{{.BranchSlug}}"
+
+// PathVars are the variables available to worktree path templates.
+type PathVars struct {
+	RepoRoot   string // absolute path of the main worktree
+	Repo       string // repository folder name (without a trailing .git for bare repositories)
+	Branch     string // branch name as given, e.g. "feature/x"
+	BranchSlug string // branch name made safe for a single folder, e.g. "feature-x"
+	Remote     string // remote the branch comes from or tracks; empty if none
+	Date       string // today's date as YYYY-MM-DD
+}
+
+// pathVars collects template variables for a worktree of branch in the
+// repository whose main worktree is root. remote may be empty.
+func pathVars(root, branch, remote string) PathVars {
+	return PathVars{
+		RepoRoot:   root,
+		Repo:       strings.TrimSuffix(filepath.Base(root), ".git"),
+		Branch:     branch,
+		BranchSlug: Slug(branch),
+		Remote:     remote,
+		Date:       time.Now().Format("2006-01-02"),
+	}
+}
+
+// WorktreePath renders tmpl (a text/template such as DefaultPathTemplate)
+// for branch and returns the resulting absolute, cleaned path.
+func WorktreePath(tmpl, branch, remote string) (string, error) {
+	root, err := MainWorktreeRoot()
+	if err != nil {
+		return "", err
+	}
+	return WorktreePathIn(root, tmpl, branch, remote)
+}
+
+// WorktreePathIn is WorktreePath for the repository whose main worktree is
+// root, already known to the caller, so it runs no git commands.
+func WorktreePathIn(root, tmpl, branch, remote string) (string, error) {
+	if tmpl == "" {
+		tmpl = DefaultPathTemplate
+	}
+	t, err := template.New("path").Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("path template: %w", err)
+	}
+	vars := pathVars(root, branch, remote)
+	var b bytes.Buffer
+	if err := t.Execute(&b, vars); err != nil {
+		return "", fmt.Errorf("path template: %w", err)
+	}
+	p := strings.TrimSpace(b.String())
+	if p == "" {
+		return "", fmt.Errorf("path template %q produced an empty path", tmpl)
+	}
+	return absIn(root, p)
+}
+
+// AbsWorktreePath expands a leading ~ and resolves relative paths against the
+// repository root, so typed and templated paths behave the same everywhere.
+func AbsWorktreePath(p string) (string, error) {
+	if filepath.IsAbs(p) || p == "~" || strings.HasPrefix(p, "~/") {
+		return absIn("", p)
+	}
+	root, err := MainWorktreeRoot()
+	if err != nil {
+		return "", err
+	}
+	return absIn(root, p)
+}
+
+// absIn is AbsWorktreePath with relative paths resolved against root.
+func absIn(root, p string) (string, error) {
+	if p == "~" || strings.HasPrefix(p, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		p = filepath.Join(home, strings.TrimPrefix(p, "~"))
+	}
+	if !filepath.IsAbs(p) {
+		p = filepath.Join(root, p)
+	}
+	return filepath.Clean(p), nil
+}
+
+// MainWorktreeRoot returns the absolute path of the main worktree (the
+// repository directory itself for bare repositories). New worktree paths are
+// based on it, so the result is the same from any subdirectory or linked worktree.
+func MainWorktreeRoot() (string, error) {
+	// git always lists the main worktree first, with an absolute path
+	wts, err := ListWorktrees()
+	if err == nil && len(wts) > 0 && filepath.IsAbs(wts[0].Path) {
+		return filepath.Clean(wts[0].Path), nil
+	}
+	// Fall back to the common git dir: <main>/.git, or the bare repository itself
+	out, cerr := runGit("rev-parse", "--path-format=absolute", "--git-common-dir")
+	if cerr != nil {
+		if err != nil {
+			return "", err
+		}
+		return "", cerr
+	}
+	common := filepath.Clean(strings.TrimSpace(out))
+	if filepath.Base(common) == ".git" {
+		return filepath.Dir(common), nil
+	}
+	return common, nil
+}
+
+var slugUnsafe = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// Slug turns a branch name into a single path segment: slashes and other
+// unsafe characters become '-', e.g. "feature/x" -> "feature-x".
+func Slug(branch string) string {
+	s := slugUnsafe.ReplaceAllString(branch, "-")
+	return strings.Trim(s, "-.")
+}
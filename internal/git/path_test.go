@@ -0,0 +1,114 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSlug(t *testing.T) {
+	tests := []struct {
+		branch, want string
+	}{
+		{"main", "main"},
+		{"feature/x", "feature-x"},
+		{"feature//x", "feature-x"},
+		{"user/ABC-123_fix.login", "user-ABC-123_fix.login"},
+		{"fix: spaces & symbols", "fix-spaces-symbols"},
+		{"/leading/and/trailing/", "leading-and-trailing"},
+		{".hidden.", "hidden"},
+		{"ünïcode", "n-code"},
+	}
+	for _, tt := range tests {
+		if got := Slug(tt.branch); got != tt.want {
+			t.Errorf("Slug(%q) = %q, want %q", tt.branch, got, tt.want)
+		}
+	}
+}
+
+func TestWorktreePathIn(t *testing.T) {
+	root := filepath.FromSlash("/src/app")
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		tmpl    string
+		branch  string
+		remote  string
+		want    string
+		wantErr string
+	}{
+		{name: "default", branch: "feature/x", want: "/src/app-feature-x"},
+		{name: "sibling folder", tmpl: "{{.RepoRoot}}/../{{.Repo}}.worktrees/{{.BranchSlug}}", branch: "feature/x", want: "/src/app.worktrees/feature-x"},
+		{name: "relative to the root", tmpl: ".worktrees/{{.Branch}}", branch: "feature/x", want: "/src/app/.worktrees/feature/x"},
+		{name: "remote", tmpl: "/wt/{{with .Remote}}{{.}}/{{end}}{{.BranchSlug}}", branch: "x", remote: "origin", want: "/wt/origin/x"},
+		{name: "no remote", tmpl: "/wt/{{with .Remote}}{{.}}/{{end}}{{.BranchSlug}}", branch: "x", want: "/wt/x"},
+		{name: "home", tmpl: "~/worktrees/{{.BranchSlug}}", branch: "x", want: filepath.Join(home, "worktrees", "x")},
+		{name: "unknown variable", tmpl: "{{.Nope}}", branch: "x", wantErr: "path template"},
+		{name: "parse error", tmpl: "{{.Repo", branch: "x", wantErr: "path template"},
+		{name: "empty result", tmpl: "{{.Remote}}", branch: "x", wantErr: "empty path"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := WorktreePathIn(root, tt.tmpl, tt.branch, tt.remote)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("WorktreePathIn() error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := filepath.FromSlash(tt.want); got != want {
+				t.Errorf("WorktreePathIn() = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestMainWorktreeRoot(t *testing.T) {
+	dir := testRepo(t)
+	commitOn(t, dir, "main", "initial")
+	linked := filepath.Join(t.TempDir(), "app-feature")
+	gitIn(t, dir, "worktree", "add", "-q", "-b", "feature", linked)
+	bare := filepath.Join(t.TempDir(), "app.git")
+	gitIn(t, dir, "clone", "-q", "--bare", dir, bare)
+	bareLinked := filepath.Join(t.TempDir(), "app-main")
+	gitIn(t, bare, "worktree", "add", "-q", bareLinked, "main")
+	for _, d := range []string{filepath.Join(dir, "sub", "dir"), filepath.Join(linked, "sub")} {
+		if err := os.MkdirAll(d, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tests := []struct {
+		name, cwd, want string
+	}{
+		{"main worktree", dir, dir},
+		{"subdirectory", filepath.Join(dir, "sub", "dir"), dir},
+		{"linked worktree", linked, dir},
+		{"linked worktree subdirectory", filepath.Join(linked, "sub"), dir},
+		{"bare repository", bare, bare},
+		{"worktree of a bare repository", bareLinked, bare},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chdir(t, tt.cwd)
+			got, err := MainWorktreeRoot()
+			if err != nil {
+				t.Fatal(err)
+			}
+			// git reports paths with symlinks resolved (e.g. /private/var on macOS)
+			want, err := filepath.EvalSymlinks(tt.want)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != want {
+				t.Errorf("MainWorktreeRoot() from %s = %q, want %q", tt.cwd, got, want)
+			}
+		})
+	}
+}
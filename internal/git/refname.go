@@ -0,0 +1,49 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidateBranchName checks name against git's rules for branch names (see
+// git-check-ref-format) without running git, so it is cheap enough to call on
+// every keystroke. CheckBranchName remains the authority before creating one.
+func ValidateBranchName(name string) error {
+	switch {
+	case name == "":
+		return fmt.Errorf("branch name is empty")
+	case name == "HEAD":
+		return fmt.Errorf("%q is not allowed as a branch name", name)
+	case strings.HasPrefix(name, "-"):
+		return fmt.Errorf("branch names can't start with '-'")
+	case strings.HasPrefix(name, "/") || strings.HasSuffix(name, "/"):
+		return fmt.Errorf("branch names can't start or end with '/'")
+	case strings.HasSuffix(name, "."):
+		return fmt.Errorf("branch names can't end with '.'")
+	case strings.Contains(name, "//"):
+		return fmt.Errorf("branch names can't contain '//'")
+	case strings.Contains(name, ".."):
+		return fmt.Errorf("branch names can't contain '..'")
+	case strings.Contains(name, "@{"):
+		return fmt.Errorf("branch names can't contain '@{'")
+	}
+	for _, r := range name {
+		switch {
+		case r == ' ':
+			return fmt.Errorf("branch names can't contain spaces")
+		case r < 0x20 || r == 0x7f:
+			return fmt.Errorf("branch names can't contain control characters")
+		case strings.ContainsRune(`~^:?*[\`, r):
+			return fmt.Errorf("branch names can't contain '%c'", r)
+		}
+	}
+	for _, part := range strings.Split(name, "/") {
+		if strings.HasPrefix(part, ".") {
+			return fmt.Errorf("branch name parts can't start with '.'")
+		}
+		if strings.HasSuffix(part, ".lock") {
+			return fmt.Errorf("branch name parts can't end with '.lock'")
+		}
+	}
+	return nil
+}
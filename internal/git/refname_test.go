@@ -0,0 +1,66 @@
+package git
+
+import (
+	"os/exec"
+	"testing"
+)
+
+func TestValidateBranchName(t *testing.T) {
+	tests := []struct {
+		name  string
+		valid bool
+	}{
+		{"main", true},
+		{"feature/x", true},
+		{"feat/ABC-123-fix-login", true},
+		{"a.b", true},
+		{"x.lockx", true},
+		{"x@y", true},
+		{"a@", true},
+		{"a{b", true},
+		{"@", true},
+		{"ünïcode", true},
+		{"refs/heads/x", true},
+		{"", false},
+		{"HEAD", false},
+		{"-x", false},
+		{"/x", false},
+		{"x/", false},
+		{"x.", false},
+		{".", false},
+		{"x//y", false},
+		{"x..y", false},
+		{"x@{y", false},
+		{"@{-1}", false},
+		{"a b", false},
+		{"a\tb", false},
+		{"a\x7fb", false},
+		{"a~b", false},
+		{"a^b", false},
+		{"a:b", false},
+		{"a?b", false},
+		{"a*b", false},
+		{"a[b", false},
+		{`a\b`, false},
+		{".x", false},
+		{"a/.x", false},
+		{"x.lock", false},
+		{"a/x.lock/b", false},
+	}
+	// git is the authority; the table documents the rules and catches drift
+	gitPath, _ := exec.LookPath("git")
+	for _, tt := range tests {
+		err := ValidateBranchName(tt.name)
+		if (err == nil) != tt.valid {
+			t.Errorf("ValidateBranchName(%q) error = %v, want valid %v", tt.name, err, tt.valid)
+		}
+		if gitPath == "" {
+			continue
+		}
+		cmd := exec.Command(gitPath, "check-ref-format", "--branch", tt.name)
+		cmd.Dir = t.TempDir() // outside a repository, so @{-1} isn't resolved
+		if gitErr := cmd.Run(); (gitErr == nil) != (err == nil) {
+			t.Errorf("ValidateBranchName(%q) error = %v, but git check-ref-format --branch error = %v", tt.name, err, gitErr)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+// Package hook runs the configured post-create commands in a new worktree.
+package hook
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// Run runs each command through the system shell in dir, one after another,
+// passing a "$ <command>" header and then every line of its combined output to
+// output. It stops at the first command that fails.
+func Run(dir string, commands []string, output func(line string)) error {
+	for _, c := range commands {
+		output("$ " + c)
+		if err := run(dir, c, output); err != nil {
+			var ee *exec.ExitError
+			if errors.As(err, &ee) {
+				return fmt.Errorf("%q exited with code %d", c, ee.ExitCode())
+			}
+			return fmt.Errorf("%q failed: %w", c, err)
+		}
+	}
+	return nil
+}
+
+func run(dir, command string, output func(string)) error {
+	cmd := shell(command)
+	cmd.Dir = dir
+	pr, pw := io.Pipe()
+	cmd.Stdout, cmd.Stderr = pw, pw
+	if err := cmd.Start(); err != nil {
+		pw.Close()
+		return err
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s := bufio.NewScanner(pr)
+		for s.Scan() {
+			line := s.Text()
+			// Progress bars redraw with '\r'; keep only the final state
+			if i := strings.LastIndexByte(line, '\r'); i >= 0 {
+				line = line[i+1:]
+			}
+			output(line)
+		}
+		// Drain anything past an overlong line so the command never blocks
+		io.Copy(io.Discard, pr)
+	}()
+	err := cmd.Wait()
+	pw.Close()
+	<-done
+	return err
+}
+
+func shell(command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.Command("cmd", "/C", command)
+	}
+	return exec.Command("sh", "-c", command)
+}
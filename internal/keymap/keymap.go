@@ -0,0 +1,161 @@
+// Package keymap defines the TUI's key bindings. The same bindings drive key
+// matching and the help footers, so remapping a key from config keeps both in sync.
+package keymap
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/key"
+)
+
+// Map holds the app's bindings. List navigation (up/down, paging) keeps the
+// defaults of the bubbles list.
+type Map struct {
+	Quit         key.Binding
+	Select       key.Binding
+	Back         key.Binding
+	Add          key.Binding
+	OpenWith     key.Binding
+	AltEdit      key.Binding
+	Delete       key.Binding
+	DeleteBranch key.Binding
+	Refresh      key.Binding
+	Fetch        key.Binding
+	NewBranch    key.Binding
+	Prefix       key.Binding
+}
+
+// Default returns the built-in bindings.
+func Default() Map {
+	return Map{
+		Quit:         bind("quit", "q"),
+		Select:       bind("select", "enter"),
+		Back:         bind("back", "esc"),
+		Add:          bind("add", "a"),
+		OpenWith:     bind("open with", "o"),
+		AltEdit:      bind("edit & return", "alt+enter"),
+		Delete:       bind("delete", "d"),
+		DeleteBranch: bind("delete with branch", "b"),
+		Refresh:      bind("refresh", "r"),
+		Fetch:        bind("fetch", "f"),
+		NewBranch:    bind("new branch", "n"),
+		Prefix:       bind("prefix", "tab"),
+	}
+}
+
+func bind(help string, keys ...string) key.Binding {
+	return key.NewBinding(key.WithKeys(keys...), key.WithHelp(strings.Join(keys, "/"), help))
+}
+
+// bindings maps config names to the fields they rebind.
+func (m *Map) bindings() map[string]*key.Binding {
+	return map[string]*key.Binding{
+		"quit":         &m.Quit,
+		"select":       &m.Select,
+		"back":         &m.Back,
+		"add":          &m.Add,
+		"openWith":     &m.OpenWith,
+		"altEdit":      &m.AltEdit,
+		"delete":       &m.Delete,
+		"deleteBranch": &m.DeleteBranch,
+		"refresh":      &m.Refresh,
+		"fetch":        &m.Fetch,
+		"newBranch":    &m.NewBranch,
+		"prefix":       &m.Prefix,
+	}
+}
+
+// Names returns the config names of all bindings, sorted.
+func Names() []string {
+	var m Map
+	var names []string
+	for n := range m.bindings() {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Set rebinds the binding called name (matched case-insensitively, like git
+// config keys) to keys, keeping its help text.
+func (m *Map) Set(name string, keys []string) error {
+	var b *key.Binding
+	for n, nb := range m.bindings() {
+		if strings.EqualFold(n, name) {
+			b = nb
+		}
+	}
+	if b == nil {
+		return fmt.Errorf("unknown key binding %q (want one of %s)", name, strings.Join(Names(), ", "))
+	}
+	if len(keys) == 0 {
+		return fmt.Errorf("%s: no keys given", name)
+	}
+	for _, k := range keys {
+		if strings.TrimSpace(k) == "" {
+			return fmt.Errorf("%s: empty key", name)
+		}
+		if k == "ctrl+c" {
+			return fmt.Errorf("%s: ctrl+c is reserved for quitting", name)
+		}
+		if b == &m.Prefix {
+			if err := checkInputKey(k); err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+		}
+	}
+	*b = bind(b.Help().Desc, keys...)
+	return nil
+}
+
+// checkInputKey rejects keys a binding used while typing can't take from the
+// text input: printable characters, and Enter and Esc, which confirm and cancel.
+func checkInputKey(k string) error {
+	if r, size := utf8.DecodeRuneInString(k); size == len(k) && unicode.IsPrint(r) {
+		return fmt.Errorf("%q would be typed into the input; use a key like tab or ctrl+p", k)
+	}
+	if k == "enter" || k == "esc" {
+		return fmt.Errorf("%s confirms or cancels the input", k)
+	}
+	return nil
+}
+
+// Bound returns the keys of every binding by config name.
+func (m *Map) Bound() map[string][]string {
+	out := map[string][]string{}
+	for n, b := range m.bindings() {
+		out[n] = b.Keys()
+	}
+	return out
+}
+
+// contexts groups the bindings that are active at the same time; a key may
+// only be used once per group.
+var contexts = [][]string{
+	// The inline delete confirmation lives in the worktree list, so deleteBranch shares its keys
+	{"quit", "select", "back", "add", "openWith", "altEdit", "delete", "deleteBranch", "refresh", "fetch"},
+	{"quit", "select", "back", "newBranch", "fetch"},
+	// Typing a new branch name
+	{"select", "back", "prefix"},
+}
+
+// Validate reports keys bound to more than one action in the same view.
+func (m *Map) Validate() error {
+	bs := m.bindings()
+	for _, ctx := range contexts {
+		seen := map[string]string{}
+		for _, name := range ctx {
+			for _, k := range bs[name].Keys() {
+				if other, ok := seen[k]; ok {
+					return fmt.Errorf("key %q is bound to both %s and %s", k, other, name)
+				}
+				seen[k] = name
+			}
+		}
+	}
+	return nil
+}
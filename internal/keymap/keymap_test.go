@@ -0,0 +1,96 @@
+package keymap
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSet(t *testing.T) {
+	tests := []struct {
+		name    string
+		keys    []string
+		want    []string
+		wantErr string
+	}{
+		{name: "delete", keys: []string{"x"}, want: []string{"x"}},
+		{name: "delete", keys: []string{"x", "delete"}, want: []string{"x", "delete"}},
+		{name: "OpenWith", keys: []string{"O"}, want: []string{"O"}},
+		{name: "openwith", keys: []string{"ctrl+o"}, want: []string{"ctrl+o"}},
+		{name: "remove", keys: []string{"x"}, wantErr: "unknown key binding"},
+		{name: "delete", keys: nil, wantErr: "no keys given"},
+		{name: "delete", keys: []string{"x", " "}, wantErr: "empty key"},
+		{name: "quit", keys: []string{"ctrl+c"}, wantErr: "reserved"},
+		// The prefix key is pressed while typing a branch name
+		{name: "prefix", keys: []string{"ctrl+p"}, want: []string{"ctrl+p"}},
+		{name: "prefix", keys: []string{"shift+tab"}, want: []string{"shift+tab"}},
+		{name: "prefix", keys: []string{"e"}, wantErr: "would be typed into the input"},
+		{name: "prefix", keys: []string{"/"}, wantErr: "would be typed into the input"},
+		{name: "prefix", keys: []string{"é"}, wantErr: "would be typed into the input"},
+		{name: "prefix", keys: []string{"enter"}, wantErr: "confirms or cancels"},
+		{name: "delete", keys: []string{"e"}, want: []string{"e"}},
+	}
+	for _, tt := range tests {
+		m := Default()
+		err := m.Set(tt.name, tt.keys)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Set(%q, %q) error = %v, want one containing %q", tt.name, tt.keys, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Set(%q, %q) error = %v", tt.name, tt.keys, err)
+			continue
+		}
+		var got []string
+		for n, k := range m.Bound() {
+			if strings.EqualFold(n, tt.name) {
+				got = k
+			}
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Set(%q, %q) bound %q", tt.name, tt.keys, got)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		set     map[string][]string
+		wantErr string
+	}{
+		{name: "defaults"},
+		{name: "swapped", set: map[string][]string{"delete": {"r"}, "refresh": {"d"}}},
+		{name: "same key in different views", set: map[string][]string{"newBranch": {"d"}}},
+		{name: "conflict in the list", set: map[string][]string{"delete": {"r"}}, wantErr: `key "r" is bound to both delete and refresh`},
+		{name: "deleteBranch conflict", set: map[string][]string{"deleteBranch": {"r"}}, wantErr: `key "r" is bound to both deleteBranch and refresh`},
+		{name: "conflict in the branch picker", set: map[string][]string{"newBranch": {"f"}}, wantErr: `key "f" is bound to both newBranch and fetch`},
+		{name: "select and back", set: map[string][]string{"back": {"enter"}}, wantErr: `key "enter" is bound to both select and back`},
+		{name: "prefix in the list", set: map[string][]string{"prefix": {"ctrl+r"}, "refresh": {"ctrl+r"}}},
+		{name: "prefix conflict", set: map[string][]string{"back": {"ctrl+p", "esc"}, "prefix": {"ctrl+p"}}, wantErr: `key "ctrl+p" is bound to both back and prefix`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := Default()
+			for _, n := range Names() {
+				if keys, ok := tt.set[n]; ok {
+					if err := m.Set(n, keys); err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+			err := m.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("Validate() error = %v, want %s", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,188 @@
+// Package mux keeps one terminal multiplexer session (tmux or zellij) per
+// worktree, named after the worktree's folder and a hash of its path.
+package mux
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Kind is a supported terminal multiplexer.
+type Kind string
+
+const (
+	Tmux   Kind = "tmux"
+	Zellij Kind = "zellij"
+)
+
+// Kinds lists the supported multiplexers in display order.
+var Kinds = []Kind{Tmux, Zellij}
+
+// Available reports whether the multiplexer's binary is on PATH.
+func Available(k Kind) bool {
+	_, err := exec.LookPath(string(k))
+	return err == nil
+}
+
+// Inside reports whether the current process runs inside a session of k.
+func Inside(k Kind) bool {
+	switch k {
+	case Tmux:
+		return os.Getenv("TMUX") != ""
+	case Zellij:
+		return os.Getenv("ZELLIJ") != ""
+	}
+	return false
+}
+
+// SessionName derives the session name for the worktree at path: its folder
+// and a short hash of the full path, e.g. "develop-3fa2c1", so worktrees with
+// the same folder name in different repositories or clones get their own
+// sessions. tmux does not allow '.' or ':' in session names, so they become '_'.
+func SessionName(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	sum := sha1.Sum([]byte(filepath.Clean(path)))
+	name := strings.NewReplacer(".", "_", ":", "_").Replace(filepath.Base(path))
+	return name + "-" + hex.EncodeToString(sum[:3])
+}
+
+// Session is a live multiplexer session.
+type Session struct {
+	Kind Kind
+	Name string
+	// Path is the session's starting directory; empty when the multiplexer
+	// doesn't report it (zellij).
+	Path string
+}
+
+// Owns reports whether s is the session of the worktree at path: it has the
+// worktree's session name and, when known, was started in the worktree.
+func (s Session) Owns(path string) bool {
+	return s.Name == SessionName(path) && (s.Path == "" || samePath(s.Path, path))
+}
+
+func samePath(a, b string) bool {
+	if filepath.Clean(a) == filepath.Clean(b) {
+		return true
+	}
+	ra, errA := filepath.EvalSymlinks(a)
+	rb, errB := filepath.EvalSymlinks(b)
+	return errA == nil && errB == nil && ra == rb
+}
+
+// ListSessions returns the live sessions of k. A multiplexer that is not
+// installed or has no server running yields no sessions rather than an error.
+func ListSessions(k Kind) ([]Session, error) {
+	if !Available(k) {
+		return nil, nil
+	}
+	var cmd *exec.Cmd
+	switch k {
+	case Tmux:
+		cmd = exec.Command("tmux", "list-sessions", "-F", "#{session_name}\t#{session_path}")
+	case Zellij:
+		cmd = exec.Command("zellij", "list-sessions", "--short", "--no-formatting")
+	default:
+		return nil, fmt.Errorf("unknown multiplexer %q", k)
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		// Both exit non-zero when no server/session exists
+		var ee *exec.ExitError
+		if errors.As(err, &ee) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var sessions []Session
+	for _, l := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		name, path, _ := strings.Cut(l, "\t")
+		if name = strings.TrimSpace(name); name != "" {
+			sessions = append(sessions, Session{Kind: k, Name: name, Path: path})
+		}
+	}
+	return sessions, nil
+}
+
+// Live returns the multiplexers that currently run the session of the worktree at path.
+func Live(path string) []Kind {
+	var kinds []Kind
+	for _, k := range Kinds {
+		sessions, _ := ListSessions(k)
+		for _, s := range sessions {
+			if s.Owns(path) {
+				kinds = append(kinds, k)
+				break
+			}
+		}
+	}
+	return kinds
+}
+
+// OpenCmd returns the command that attaches the terminal to the session for
+// the worktree at path, creating it with path as its working directory if
+// needed. Inside tmux it switches the current client instead of nesting.
+func OpenCmd(k Kind, path string) (*exec.Cmd, error) {
+	name := SessionName(path)
+	switch k {
+	case Tmux:
+		sessions, err := ListSessions(Tmux)
+		if err != nil {
+			return nil, err
+		}
+		exists := false
+		for _, s := range sessions {
+			if s.Name != name {
+				continue
+			}
+			// A session by this name started elsewhere isn't this worktree's to attach to
+			if !s.Owns(path) {
+				return nil, fmt.Errorf("tmux session %s already exists for %s", name, s.Path)
+			}
+			exists = true
+		}
+		if !exists {
+			if out, err := exec.Command("tmux", "new-session", "-d", "-s", name, "-c", path).CombinedOutput(); err != nil {
+				return nil, fmt.Errorf("tmux new-session failed: %v\n%s", err, out)
+			}
+		}
+		if Inside(Tmux) {
+			return exec.Command("tmux", "switch-client", "-t", "="+name), nil
+		}
+		return exec.Command("tmux", "attach-session", "-t", "="+name), nil
+	case Zellij:
+		if Inside(Zellij) {
+			return nil, fmt.Errorf("already inside zellij; detach first to switch to %s", name)
+		}
+		// attach --create starts the session in the working directory, set by the caller
+		cmd := exec.Command("zellij", "attach", "--create", name)
+		cmd.Dir = path
+		return cmd, nil
+	}
+	return nil, fmt.Errorf("unknown multiplexer %q", k)
+}
+
+// KillSession ends the session named name.
+func KillSession(k Kind, name string) error {
+	var cmd *exec.Cmd
+	switch k {
+	case Tmux:
+		cmd = exec.Command("tmux", "kill-session", "-t", "="+name)
+	case Zellij:
+		cmd = exec.Command("zellij", "kill-session", name)
+	default:
+		return fmt.Errorf("unknown multiplexer %q", k)
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s kill-session %s failed: %v\n%s", k, name, err, out)
+	}
+	return nil
+}
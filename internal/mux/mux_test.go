@@ -0,0 +1,57 @@
+package mux
+
+import (
+	"path/filepath"
+	"regexp"
+	"testing"
+)
+
+func TestSessionName(t *testing.T) {
+	valid := regexp.MustCompile(`^[^.:]+-[0-9a-f]{6}$`)
+	paths := []string{
+		filepath.FromSlash("/src/app.worktrees/develop"),
+		filepath.FromSlash("/src/other.worktrees/develop"),
+		filepath.FromSlash("/home/me/clone2/app-fix-login"),
+		filepath.FromSlash("/home/me/clone1/app-fix-login"),
+		filepath.FromSlash("/src/app-v1.2:rc"),
+	}
+	seen := map[string]string{}
+	for _, p := range paths {
+		name := SessionName(p)
+		if !valid.MatchString(name) {
+			t.Errorf("SessionName(%q) = %q, want <folder>-<hash> without '.' or ':'", p, name)
+		}
+		if other, ok := seen[name]; ok {
+			t.Errorf("SessionName(%q) = SessionName(%q) = %q", p, other, name)
+		}
+		seen[name] = p
+		if again := SessionName(p + string(filepath.Separator)); again != name {
+			t.Errorf("SessionName(%q) = %q, want %q as without the trailing separator", p+string(filepath.Separator), again, name)
+		}
+	}
+	if got, want := SessionName(filepath.FromSlash("/src/app.worktrees/develop"))[:8], "develop-"; got != want {
+		t.Errorf("SessionName() starts with %q, want %q", got, want)
+	}
+}
+
+func TestSessionOwns(t *testing.T) {
+	path := filepath.FromSlash("/src/app.worktrees/develop")
+	other := filepath.FromSlash("/src/other.worktrees/develop")
+	name := SessionName(path)
+	tests := []struct {
+		name string
+		s    Session
+		want bool
+	}{
+		{"tmux session started in the worktree", Session{Kind: Tmux, Name: name, Path: path}, true},
+		{"zellij session without a path", Session{Kind: Zellij, Name: name}, true},
+		{"same name started elsewhere", Session{Kind: Tmux, Name: name, Path: other}, false},
+		{"another worktree's session", Session{Kind: Tmux, Name: SessionName(other), Path: other}, false},
+		{"folder name only", Session{Kind: Tmux, Name: "develop", Path: path}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.s.Owns(path); got != tt.want {
+			t.Errorf("%s: Owns() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
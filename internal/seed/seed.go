@@ -0,0 +1,184 @@
+// Package seed copies or symlinks files git does not track (.env, editor
+// settings, local certificates) from the main worktree into a new one.
+package seed
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Result lists what Apply did, as paths relative to the worktree.
+type Result struct {
+	Copied  []string
+	Linked  []string
+	Skipped []string // already present in the new worktree
+}
+
+// Empty reports whether nothing matched.
+func (r Result) Empty() bool {
+	return len(r.Copied)+len(r.Linked)+len(r.Skipped) == 0
+}
+
+// Summary describes the result in one line, e.g.
+// "copied .env, .vscode/settings.json; linked certs; skipped 1 existing".
+func (r Result) Summary() string {
+	var parts []string
+	if len(r.Copied) > 0 {
+		parts = append(parts, "copied "+strings.Join(r.Copied, ", "))
+	}
+	if len(r.Linked) > 0 {
+		parts = append(parts, "linked "+strings.Join(r.Linked, ", "))
+	}
+	if len(r.Skipped) > 0 {
+		parts = append(parts, fmt.Sprintf("skipped %d existing", len(r.Skipped)))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// Validate checks that pattern is a well-formed glob relative to the worktree root.
+func Validate(pattern string) error {
+	if filepath.IsAbs(pattern) {
+		return fmt.Errorf("pattern %q must be relative to the worktree", pattern)
+	}
+	for _, part := range strings.Split(filepath.ToSlash(pattern), "/") {
+		if part == ".." {
+			return fmt.Errorf("pattern %q must not leave the worktree", pattern)
+		}
+	}
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		return fmt.Errorf("pattern %q: %w", pattern, err)
+	}
+	return nil
+}
+
+// Apply copies the files matching copyPatterns and symlinks those matching
+// linkPatterns from src into dst, keeping their relative paths. Patterns use
+// filepath.Match syntax; a matched directory is copied recursively or linked as
+// a whole. Files that already exist in dst are left alone. Apply stops at the
+// first error, returning what it did so far.
+func Apply(src, dst string, copyPatterns, linkPatterns []string) (Result, error) {
+	var r Result
+	for _, p := range copyPatterns {
+		rels, err := match(src, p)
+		if err != nil {
+			return r, err
+		}
+		for _, rel := range rels {
+			if exists(filepath.Join(dst, rel)) {
+				r.Skipped = append(r.Skipped, rel)
+				continue
+			}
+			if err := copyTree(filepath.Join(src, rel), filepath.Join(dst, rel)); err != nil {
+				return r, err
+			}
+			r.Copied = append(r.Copied, rel)
+		}
+	}
+	for _, p := range linkPatterns {
+		rels, err := match(src, p)
+		if err != nil {
+			return r, err
+		}
+		for _, rel := range rels {
+			target := filepath.Join(dst, rel)
+			if exists(target) {
+				r.Skipped = append(r.Skipped, rel)
+				continue
+			}
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return r, err
+			}
+			if err := os.Symlink(filepath.Join(src, rel), target); err != nil {
+				return r, err
+			}
+			r.Linked = append(r.Linked, rel)
+		}
+	}
+	return r, nil
+}
+
+// match returns the paths under root matching pattern, relative to root and
+// sorted, never descending into .git.
+func match(root, pattern string) ([]string, error) {
+	paths, err := filepath.Glob(filepath.Join(root, pattern))
+	if err != nil {
+		return nil, fmt.Errorf("pattern %q: %w", pattern, err)
+	}
+	var rels []string
+	for _, p := range paths {
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return nil, err
+		}
+		if rel == ".git" || strings.HasPrefix(rel, ".git"+string(filepath.Separator)) {
+			continue
+		}
+		rels = append(rels, rel)
+	}
+	sort.Strings(rels)
+	return rels, nil
+}
+
+func exists(path string) bool {
+	_, err := os.Lstat(path)
+	return err == nil || !errors.Is(err, fs.ErrNotExist)
+}
+
+// copyTree copies a file, symlink or directory from src to dst, skipping any
+// entries that already exist below dst.
+func copyTree(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if d.IsDir() {
+			return os.MkdirAll(target, 0o755)
+		}
+		if exists(target) {
+			return nil
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return err
+		}
+		if d.Type()&fs.ModeSymlink != 0 {
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		}
+		return copyFile(path, target)
+	})
+}
+
+func copyFile(src, dst string) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,107 @@
+package seed
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		pattern string
+		wantErr bool
+	}{
+		{".env", false},
+		{".env*", false},
+		{".vscode/*.json", false},
+		{"config/[a-z]*.local", false},
+		{"../secrets", true},
+		{"config/../../secrets", true},
+		{"/etc/passwd", true},
+		{"[", true},
+		{"config/[a-", true},
+	}
+	for _, tt := range tests {
+		if err := Validate(tt.pattern); (err != nil) != tt.wantErr {
+			t.Errorf("Validate(%q) error = %v, wantErr %v", tt.pattern, err, tt.wantErr)
+		}
+	}
+}
+
+func TestMatch(t *testing.T) {
+	root := t.TempDir()
+	for _, f := range []string{".env", ".env.local", "README.md", ".vscode/settings.json", ".vscode/launch.json", ".git/config", "certs/dev.pem"} {
+		writeFile(t, filepath.Join(root, f), "x")
+	}
+	tests := []struct {
+		pattern string
+		want    []string
+	}{
+		{".env", []string{".env"}},
+		{".env*", []string{".env", ".env.local"}},
+		{".vscode/*.json", []string{filepath.FromSlash(".vscode/launch.json"), filepath.FromSlash(".vscode/settings.json")}},
+		{"certs", []string{"certs"}},
+		{".git", nil},
+		{".g*", nil},
+		{".git/*", nil},
+		{"missing", nil},
+	}
+	for _, tt := range tests {
+		got, err := match(root, tt.pattern)
+		if err != nil {
+			t.Errorf("match(%q) error = %v", tt.pattern, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("match(%q) = %q, want %q", tt.pattern, got, tt.want)
+		}
+	}
+	if _, err := match(root, "["); err == nil {
+		t.Errorf("match(%q) succeeded, want an error", "[")
+	}
+}
+
+func TestApplyCopiesAndSkipsExisting(t *testing.T) {
+	src, dst := t.TempDir(), t.TempDir()
+	writeFile(t, filepath.Join(src, ".env"), "SECRET=1")
+	writeFile(t, filepath.Join(src, ".vscode", "settings.json"), "{}")
+	writeFile(t, filepath.Join(dst, ".env"), "SECRET=kept")
+
+	r, err := Apply(src, dst, []string{".env", ".vscode"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Result{Copied: []string{".vscode"}, Skipped: []string{".env"}}
+	if !reflect.DeepEqual(r, want) {
+		t.Errorf("Apply() = %+v, want %+v", r, want)
+	}
+	if got := readFile(t, filepath.Join(dst, ".env")); got != "SECRET=kept" {
+		t.Errorf(".env = %q, want the existing file kept", got)
+	}
+	if got := readFile(t, filepath.Join(dst, ".vscode", "settings.json")); got != "{}" {
+		t.Errorf(".vscode/settings.json = %q, want a copy", got)
+	}
+	if got := r.Summary(); got != "copied .vscode; skipped 1 existing" {
+		t.Errorf("Summary() = %q", got)
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
@@ -0,0 +1,85 @@
+// Package shellwords splits command lines into words the way a POSIX shell
+// would, without performing any expansion.
+package shellwords
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Split breaks s into words. Whitespace separates words; single quotes keep
+// their content literally; double quotes keep whitespace and allow \" \\ \$ and
+// \` escapes; outside quotes a backslash escapes the next character.
+func Split(s string) ([]string, error) {
+	var (
+		words  []string
+		cur    strings.Builder
+		inWord bool
+	)
+	rs := []rune(s)
+	for i := 0; i < len(rs); i++ {
+		r := rs[i]
+		switch r {
+		case ' ', '\t', '\n':
+			if inWord {
+				words = append(words, cur.String())
+				cur.Reset()
+				inWord = false
+			}
+		case '\\':
+			if i+1 >= len(rs) {
+				return nil, fmt.Errorf("trailing backslash in %q", s)
+			}
+			i++
+			cur.WriteRune(rs[i])
+			inWord = true
+		case '\'':
+			end := indexRune(rs, i+1, '\'')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated ' quote in %q", s)
+			}
+			cur.WriteString(string(rs[i+1 : end]))
+			i = end
+			inWord = true
+		case '"':
+			i++
+			for ; i < len(rs) && rs[i] != '"'; i++ {
+				if rs[i] == '\\' && i+1 < len(rs) && strings.ContainsRune("\"\\$`", rs[i+1]) {
+					i++
+				}
+				cur.WriteRune(rs[i])
+			}
+			if i >= len(rs) {
+				return nil, fmt.Errorf("unterminated \" quote in %q", s)
+			}
+			inWord = true
+		default:
+			cur.WriteRune(r)
+			inWord = true
+		}
+	}
+	if inWord {
+		words = append(words, cur.String())
+	}
+	return words, nil
+}
+
+func indexRune(rs []rune, from int, r rune) int {
+	for i := from; i < len(rs); i++ {
+		if rs[i] == r {
+			return i
+		}
+	}
+	return -1
+}
+
+// Quote returns s quoted for safe use as a single word in a POSIX shell command.
+func Quote(s string) string {
+	if s == "" {
+		return "''"
+	}
+	if !strings.ContainsAny(s, " \t\n'\"\\$`!*?[]{}()<>|&;#~") {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
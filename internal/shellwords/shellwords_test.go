@@ -0,0 +1,75 @@
+package shellwords
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    []string
+		wantErr string
+	}{
+		{in: "", want: nil},
+		{in: "   ", want: nil},
+		{in: "code", want: []string{"code"}},
+		{in: "code  --wait\t-n\n", want: []string{"code", "--wait", "-n"}},
+		{in: `"/Applications/My Editor" --new`, want: []string{"/Applications/My Editor", "--new"}},
+		{in: `'it''s'`, want: []string{"its"}},
+		{in: `'a "b" \c'`, want: []string{`a "b" \c`}},
+		{in: `"a \"b\" \$x \\ \n"`, want: []string{`a "b" $x \ \n`}},
+		{in: `a\ b c\\d`, want: []string{"a b", `c\d`}},
+		{in: `pre"mid dle"post`, want: []string{"premid dlepost"}},
+		{in: `'' ""`, want: []string{"", ""}},
+		{in: `sh -c "cd {{quote .Path}} && make"`, want: []string{"sh", "-c", "cd {{quote .Path}} && make"}},
+		{in: "ünï 'cödé'", want: []string{"ünï", "cödé"}},
+		{in: `a\`, wantErr: "trailing backslash"},
+		{in: `'open`, wantErr: "unterminated ' quote"},
+		{in: `"open`, wantErr: `unterminated " quote`},
+		{in: `"open\"`, wantErr: `unterminated " quote`},
+	}
+	for _, tt := range tests {
+		got, err := Split(tt.in)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Split(%q) error = %v, want one containing %q", tt.in, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Split(%q) error = %v", tt.in, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Split(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestQuote(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"", "''"},
+		{"plain", "plain"},
+		{"/src/app-feature_x.1", "/src/app-feature_x.1"},
+		{"my app", "'my app'"},
+		{"it's", `'it'\''s'`},
+		{"$HOME", "'$HOME'"},
+		{"a;rm -rf", "'a;rm -rf'"},
+		{"~/src", "'~/src'"},
+	}
+	for _, tt := range tests {
+		got := Quote(tt.in)
+		if got != tt.want {
+			t.Errorf("Quote(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+		// Quoting must round-trip through Split as a single word
+		words, err := Split(got)
+		if err != nil || len(words) != 1 || words[0] != tt.in {
+			t.Errorf("Split(Quote(%q)) = %q, %v; want the original word", tt.in, words, err)
+		}
+	}
+}
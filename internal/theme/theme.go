@@ -1,8 +1,21 @@
+// Package theme holds the active color palette. The TUI styles read the
+// package-level colors, so Apply must run before the styles are built.
 package theme
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 
-// Catppuccin Mocha palette (subset)
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// Active palette, Catppuccin Mocha until Apply is called. Names follow Catppuccin.
 var (
 	// Core
 	Base     = lipgloss.Color("#1e1e2e")
@@ -31,3 +44,171 @@ var (
 	BorderUnfocused = Surface2
 	BorderFocused   = Mauve
 )
+
+// Palette is a complete set of colors for Apply.
+type Palette struct {
+	Base, Mantle, Crust, Text, Subtext0, Surface0, Surface1, Surface2 lipgloss.Color
+	Mauve, Lavender, Blue, Green, Peach, Red, Sky, Yellow             lipgloss.Color
+}
+
+// Special theme names besides the built-in palettes.
+const (
+	// Auto picks Mocha on dark terminals and Latte on light ones, or None when
+	// NO_COLOR is set.
+	Auto = "auto"
+	// None renders without colors.
+	None = "none"
+)
+
+// Builtin are the named palettes shipped with the app.
+var Builtin = map[string]Palette{
+	"mocha": {
+		Base: "#1e1e2e", Mantle: "#181825", Crust: "#11111b", Text: "#cdd6f4", Subtext0: "#a6adc8",
+		Surface0: "#313244", Surface1: "#45475a", Surface2: "#585b70",
+		Mauve: "#cba6f7", Lavender: "#b4befe", Blue: "#89b4fa", Green: "#a6e3a1",
+		Peach: "#fab387", Red: "#f38ba8", Sky: "#89dceb", Yellow: "#f9e2af",
+	},
+	"macchiato": {
+		Base: "#24273a", Mantle: "#1e2030", Crust: "#181926", Text: "#cad3f5", Subtext0: "#a5adcb",
+		Surface0: "#363a4f", Surface1: "#494d64", Surface2: "#5b6078",
+		Mauve: "#c6a0f6", Lavender: "#b7bdf8", Blue: "#8aadf4", Green: "#a6da95",
+		Peach: "#f5a97f", Red: "#ed8796", Sky: "#91d7e3", Yellow: "#eed49f",
+	},
+	"frappe": {
+		Base: "#303446", Mantle: "#292c3c", Crust: "#232634", Text: "#c6d0f5", Subtext0: "#a5adce",
+		Surface0: "#414559", Surface1: "#51576d", Surface2: "#626880",
+		Mauve: "#ca9ee6", Lavender: "#babbf1", Blue: "#8caaee", Green: "#a6d189",
+		Peach: "#ef9f76", Red: "#e78284", Sky: "#99d1db", Yellow: "#e5c890",
+	},
+	// Latte's surfaces are swapped for its overlay shades so descriptions stay readable
+	"latte": {
+		Base: "#eff1f5", Mantle: "#e6e9ef", Crust: "#dce0e8", Text: "#4c4f69", Subtext0: "#6c6f85",
+		Surface0: "#ccd0da", Surface1: "#9ca0b0", Surface2: "#8c8fa1",
+		Mauve: "#8839ef", Lavender: "#7287fd", Blue: "#1e66f5", Green: "#40a02b",
+		Peach: "#fe640b", Red: "#d20f39", Sky: "#04a5e5", Yellow: "#df8e1d",
+	},
+	// Plain light theme with saturated accents for white backgrounds
+	"light": {
+		Base: "#ffffff", Mantle: "#f6f8fa", Crust: "#ffffff", Text: "#1f2328", Subtext0: "#57606a",
+		Surface0: "#d0d7de", Surface1: "#6e7781", Surface2: "#8c959f",
+		Mauve: "#8250df", Lavender: "#0969da", Blue: "#0969da", Green: "#1a7f37",
+		Peach: "#bc4c00", Red: "#cf222e", Sky: "#1b7c83", Yellow: "#9a6700",
+	},
+	// ANSI colors, so the terminal's own (usually high-contrast) palette applies
+	"high-contrast": {
+		Base: "0", Mantle: "0", Crust: "0", Text: "15", Subtext0: "15",
+		Surface0: "8", Surface1: "7", Surface2: "7",
+		Mauve: "13", Lavender: "14", Blue: "12", Green: "10",
+		Peach: "11", Red: "9", Sky: "14", Yellow: "11",
+	},
+}
+
+// Names returns every valid theme name, including Auto and None.
+func Names(custom map[string]Palette) []string {
+	names := []string{Auto, None}
+	for n := range Builtin {
+		names = append(names, n)
+	}
+	for n := range custom {
+		if _, ok := Builtin[n]; !ok {
+			names = append(names, n)
+		}
+	}
+	sort.Strings(names[2:])
+	return names
+}
+
+// Lookup finds a palette by name; custom palettes shadow built-in ones.
+// "frappé" is accepted as an alias of "frappe".
+func Lookup(name string, custom map[string]Palette) (Palette, bool) {
+	if p, ok := custom[name]; ok {
+		return p, true
+	}
+	if name == "frappé" {
+		name = "frappe"
+	}
+	p, ok := Builtin[name]
+	return p, ok
+}
+
+// Apply makes p the active palette.
+func Apply(p Palette) {
+	Base, Mantle, Crust, Text, Subtext0 = p.Base, p.Mantle, p.Crust, p.Text, p.Subtext0
+	Surface0, Surface1, Surface2 = p.Surface0, p.Surface1, p.Surface2
+	Mauve, Lavender, Blue, Green, Peach, Red, Sky, Yellow = p.Mauve, p.Lavender, p.Blue, p.Green, p.Peach, p.Red, p.Sky, p.Yellow
+	BorderUnfocused, BorderFocused = Surface2, Mauve
+}
+
+// Use activates the theme called name. Auto detects the terminal background,
+// and None (or Auto with NO_COLOR set) turns colors off entirely.
+func Use(name string, custom map[string]Palette) error {
+	if name == "" || name == Auto {
+		switch {
+		case os.Getenv("NO_COLOR") != "":
+			name = None
+		case lipgloss.HasDarkBackground():
+			name = "mocha"
+		default:
+			name = "latte"
+		}
+	}
+	if name == None {
+		lipgloss.SetColorProfile(termenv.Ascii)
+		return nil
+	}
+	p, ok := Lookup(name, custom)
+	if !ok {
+		return fmt.Errorf("unknown theme %q (want one of %s)", name, strings.Join(Names(custom), ", "))
+	}
+	Apply(p)
+	return nil
+}
+
+var hexColor = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// ParseColor validates a color as "#rgb", "#rrggbb" or an ANSI color number (0-255).
+func ParseColor(s string) (lipgloss.Color, error) {
+	if hexColor.MatchString(s) {
+		return lipgloss.Color(s), nil
+	}
+	if n, err := strconv.Atoi(s); err == nil && n >= 0 && n <= 255 {
+		return lipgloss.Color(s), nil
+	}
+	return "", fmt.Errorf("invalid color %q (want #rrggbb, #rgb or 0-255)", s)
+}
+
+// ColorNames lists the palette's color names as used in config, e.g. "surface0".
+func ColorNames() []string {
+	t := reflect.TypeOf(Palette{})
+	names := make([]string, t.NumField())
+	for i := range names {
+		names[i] = strings.ToLower(t.Field(i).Name)
+	}
+	return names
+}
+
+// Set overrides the color called name (case-insensitive, see ColorNames).
+func (p *Palette) Set(name, value string) error {
+	c, err := ParseColor(value)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	v := reflect.ValueOf(p).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		if strings.EqualFold(v.Type().Field(i).Name, name) {
+			v.Field(i).Set(reflect.ValueOf(c))
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown color %q (want one of %s)", name, strings.Join(ColorNames(), ", "))
+}
+
+// Colors returns the palette as config name → color, the inverse of Set.
+func (p Palette) Colors() map[string]string {
+	v := reflect.ValueOf(p)
+	out := map[string]string{}
+	for i := 0; i < v.NumField(); i++ {
+		out[strings.ToLower(v.Type().Field(i).Name)] = string(v.Field(i).Interface().(lipgloss.Color))
+	}
+	return out
+}
@@ -0,0 +1,69 @@
+package theme
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestParseColor(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    lipgloss.Color
+		wantErr bool
+	}{
+		{in: "#ea76cb", want: "#ea76cb"},
+		{in: "#EA76CB", want: "#EA76CB"},
+		{in: "#fff", want: "#fff"},
+		{in: "0", want: "0"},
+		{in: "255", want: "255"},
+		{in: "", wantErr: true},
+		{in: "ea76cb", wantErr: true},
+		{in: "#ea76c", wantErr: true},
+		{in: "#ea76cbff", wantErr: true},
+		{in: "#ggg", wantErr: true},
+		{in: "256", wantErr: true},
+		{in: "-1", wantErr: true},
+		{in: "pink", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseColor(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseColor(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseColor(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestPaletteSet(t *testing.T) {
+	tests := []struct {
+		name, value string
+		wantErr     string
+	}{
+		{name: "mauve", value: "#ea76cb"},
+		{name: "Surface0", value: "236"},
+		{name: "purple", value: "#ea76cb", wantErr: "unknown color"},
+		{name: "mauve", value: "pink", wantErr: "mauve: invalid color"},
+	}
+	for _, tt := range tests {
+		var p Palette
+		err := p.Set(tt.name, tt.value)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Set(%q, %q) error = %v, want one containing %q", tt.name, tt.value, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Set(%q, %q) error = %v", tt.name, tt.value, err)
+			continue
+		}
+		if got := p.Colors()[strings.ToLower(tt.name)]; got != tt.value {
+			t.Errorf("after Set(%q, %q), Colors() has %q", tt.name, tt.value, got)
+		}
+	}
+}
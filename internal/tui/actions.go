@@ -0,0 +1,101 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/fredrikmwold/git-worktree-tui/internal/action"
+	"github.com/fredrikmwold/git-worktree-tui/internal/git"
+	"github.com/fredrikmwold/git-worktree-tui/internal/theme"
+)
+
+// actionDoneMsg reports that an action run in the terminal has exited.
+type actionDoneMsg struct {
+	act action.Action
+	wt  git.Worktree
+	err error
+}
+
+// openActionMenu lists the available actions for wt.
+func (m *model) openActionMenu(wt git.Worktree) {
+	labelMode := func(s string) string { return lipgloss.NewStyle().Foreground(theme.Blue).Render(s) }
+	items := make([]list.Item, 0, len(m.actions))
+	for _, a := range m.actions {
+		desc := labelMode("["+string(a.Mode)+"]") + " " + a.Description
+		items = append(items, item{title: a.Name, desc: desc, act: a.Name})
+	}
+	m.actionList.SetItems(items)
+	m.actionList.Select(0)
+	m.actionList.Title = fmt.Sprintf("Open %s", filepath.Base(wt.Path))
+	m.selected = wt
+	m.state = stateActions
+}
+
+// runAction runs the named action for wt according to its mode.
+func (m *model) runAction(name string, wt git.Worktree) tea.Cmd {
+	return m.runActionWith(name, wt, false)
+}
+
+// runActionWith is runAction with an optional per-invocation flip between the
+// quit and stay modes (e.g. alt+enter for the editor).
+func (m *model) runActionWith(name string, wt git.Worktree, flipStay bool) tea.Cmd {
+	m.state = stateList
+	a, ok := action.Find(m.actions, name)
+	if !ok {
+		return m.list.NewStatusMessage(fmt.Sprintf("Unknown action %s", name))
+	}
+	if flipStay {
+		switch a.Mode {
+		case action.ModeQuit:
+			a.Mode = action.ModeStay
+		case action.ModeStay:
+			a.Mode = action.ModeQuit
+		}
+	}
+	cmd, err := a.Cmd(m.actionVars(wt))
+	if err != nil {
+		return m.list.NewStatusMessage(fmt.Sprintf("Error: %s: %v", a.Name, err))
+	}
+	if a.Mode == action.ModeBackground {
+		if err := action.Start(cmd); err != nil {
+			return m.list.NewStatusMessage(fmt.Sprintf("Error: %s: %v", a.Name, err))
+		}
+		return m.list.NewStatusMessage(fmt.Sprintf("Started %s for %s", a.Name, filepath.Base(wt.Path)))
+	}
+	return tea.ExecProcess(cmd, func(err error) tea.Msg { return actionDoneMsg{act: a, wt: wt, err: err} })
+}
+
+// actionDone handles the return from a terminal action: quit, or refresh the
+// list (the action may have changed the worktree) and report failures. A failed
+// quit-mode action stays in the TUI so its error isn't lost.
+func (m *model) actionDone(msg actionDoneMsg) tea.Cmd {
+	if msg.err == nil {
+		if msg.act.Mode == action.ModeQuit {
+			return tea.Quit
+		}
+		return loadWorktrees
+	}
+	var ee *exec.ExitError
+	text := fmt.Sprintf("Error: %s: %v", msg.act.Name, msg.err)
+	if errors.As(msg.err, &ee) {
+		text = fmt.Sprintf("%s exited with code %d", msg.act.Name, ee.ExitCode())
+	}
+	return tea.Batch(loadWorktrees, m.list.NewStatusMessage(text))
+}
+
+// actionVars collects template values for wt.
+func (m model) actionVars(wt git.Worktree) action.Vars {
+	v := action.Vars{Path: wt.Path, Branch: wt.BranchName(), Name: filepath.Base(wt.Path)}
+	for _, li := range m.list.Items() {
+		if it, ok := li.(item); ok && it.wt.IsMain {
+			v.Repo = filepath.Base(it.wt.Path)
+			break
+		}
+	}
+	return v
+}
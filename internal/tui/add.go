@@ -0,0 +1,377 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/fredrikmwold/git-worktree-tui/internal/git"
+	"github.com/fredrikmwold/git-worktree-tui/internal/seed"
+	"github.com/fredrikmwold/git-worktree-tui/internal/theme"
+)
+
+const (
+	addBranchLabel = "[+] Create new branch"
+	addBranchDesc  = "Type a new branch name"
+	addRefLabel    = "[+] Enter a ref or commit"
+)
+
+// addRequest describes the worktree being created by the add flow.
+type addRequest struct {
+	branch string
+	// fromRef is the start point when creating a new branch; empty to check out an existing branch.
+	fromRef string
+	// remote the branch comes from or tracks, exposed to the path template
+	remote string
+	// isNew marks a branch named by the user, which must follow the naming policy
+	isNew bool
+}
+
+type loadedRefsMsg struct {
+	refs []git.Ref
+	def  string
+	err  error
+}
+
+func loadRefs() tea.Msg {
+	refs, err := git.ListRefs()
+	if err != nil {
+		return loadedRefsMsg{err: err}
+	}
+	// Best effort: without a default branch the list simply isn't reordered
+	def, _ := git.DefaultBranch()
+	return loadedRefsMsg{refs: refs, def: def}
+}
+
+// activeList returns the list shown in the current state, used for status messages.
+func (m *model) activeList() *list.Model {
+	switch m.state {
+	case stateAddPick:
+		return &m.branches
+	case stateAddBase:
+		return &m.bases
+	}
+	return &m.list
+}
+
+// openAddPicker switches to the branch picker with a fresh filter and reloads branches.
+func (m *model) openAddPicker() tea.Cmd {
+	m.state = stateAddPick
+	m.branches.ResetFilter()
+	m.branchErr = ""
+	return loadBranches
+}
+
+// createQuery returns the branch picker's filter text when it names no listed
+// branch, so the add item can offer to create it; "" otherwise.
+func (m *model) createQuery() string {
+	if m.branches.FilterState() == list.Unfiltered {
+		return ""
+	}
+	q := strings.TrimSpace(m.branches.FilterValue())
+	if q == "" {
+		return ""
+	}
+	for _, li := range m.branches.Items() {
+		if it, ok := li.(item); ok && !it.isAdd && it.br.Name == q {
+			return ""
+		}
+	}
+	return q
+}
+
+// createFromFilter starts a new branch named after the filter text, or shows
+// why git would reject the name on the add item.
+func (m *model) createFromFilter() tea.Cmd {
+	branch := m.createQuery()
+	if !m.checkBranchName(branch) {
+		return nil
+	}
+	if m.branches.SettingFilter() {
+		// Keep the filter applied for when Esc comes back to the picker
+		m.branches, _ = m.branches.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	}
+	return m.pickBaseRef(branch)
+}
+
+// checkBranchName reports whether branch is a valid new branch name, otherwise
+// keeping the reason in branchErr for the add item to show.
+func (m *model) checkBranchName(branch string) bool {
+	m.branchErr = ""
+	if problem, warn := m.branchProblem(branch); problem != "" && !warn {
+		m.branchErr = problem
+	} else if err := git.CheckBranchName(branch); err != nil {
+		m.branchErr = err.Error()
+	}
+	m.updateAddItemTitle(m.input.Value())
+	return m.branchErr == ""
+}
+
+// promptPath opens the path prompt for req, prefilled from the configured path template.
+func (m *model) promptPath(req addRequest) tea.Cmd {
+	m.pending = req
+	m.pathBack = m.state
+	m.pathErr = ""
+	path, err := git.WorktreePath(m.cfg.PathTemplate, req.branch, req.remote)
+	if err != nil {
+		m.pathErr = err.Error()
+	}
+	m.pathInput.SetValue(path)
+	m.pathInput.CursorEnd()
+	m.state = stateAddPath
+	return m.pathInput.Focus()
+}
+
+// createWorktree runs the git command for req at path and returns to the worktree list.
+// On failure the path prompt stays open with the error shown below the input.
+func (m *model) createWorktree(req addRequest, path string) tea.Cmd {
+	path, err := git.AbsWorktreePath(path)
+	if err == nil && req.isNew {
+		err = m.namer.Check(req.branch)
+	}
+	if err == nil {
+		if req.fromRef != "" {
+			err = git.CreateWorktreeFromRef(req.branch, path, req.fromRef)
+		} else {
+			err = git.CreateWorktree(req.branch, path, false)
+		}
+	}
+	if err != nil {
+		m.pathErr = err.Error()
+		return nil
+	}
+	m.pathInput.Blur()
+	m.state = stateList
+	msg := fmt.Sprintf("Created worktree %s", filepath.Base(path))
+	if req.fromRef != "" {
+		msg += " from " + req.fromRef
+	}
+	msg += m.seedWorktree(path)
+	if len(m.cfg.PostCreate) == 0 {
+		return tea.Batch(loadWorktrees, m.list.NewStatusMessage(msg))
+	}
+	if m.hooking {
+		// Only one hook run streams into the log pane at a time
+		msg += fmt.Sprintf("; post-create hooks skipped while %s is still being set up", m.hookName)
+		return tea.Batch(loadWorktrees, m.list.NewStatusMessage(msg))
+	}
+	return tea.Batch(loadWorktrees, m.list.NewStatusMessage(msg), m.startHooks(path))
+}
+
+// seedWorktree copies and symlinks the configured untracked files from the main
+// worktree into the new worktree at path, before any hooks run. It returns a
+// note to append to the status message ("" when nothing matched).
+func (m *model) seedWorktree(path string) string {
+	if len(m.cfg.Copy) == 0 && len(m.cfg.Symlink) == 0 {
+		return ""
+	}
+	src, err := git.MainWorktreeRoot()
+	if err != nil {
+		return fmt.Sprintf("; could not copy files: %v", err)
+	}
+	r, err := seed.Apply(src, path, m.cfg.Copy, m.cfg.Symlink)
+	var note string
+	if !r.Empty() {
+		note = "; " + r.Summary()
+	}
+	if err != nil {
+		note += fmt.Sprintf("; could not copy files: %v", err)
+	}
+	return note
+}
+
+// pathView renders the path prompt.
+func (m model) pathView() string {
+	title := m.list.Styles.Title.Render("Worktree path")
+	label := lipgloss.NewStyle().Foreground(theme.Sky)
+	muted := lipgloss.NewStyle().Foreground(theme.Surface2)
+	var b strings.Builder
+	b.WriteString(title + "\n\n")
+	b.WriteString(label.Render("Branch:") + " " + m.pending.branch)
+	if m.pending.fromRef != "" {
+		b.WriteString("  " + label.Render("From:") + " " + m.pending.fromRef)
+	}
+	b.WriteString("\n\n")
+	b.WriteString(m.pathInput.View() + "\n")
+	if m.pathErr != "" {
+		b.WriteString("\n" + lipgloss.NewStyle().Foreground(theme.Red).Render(m.pathErr) + "\n")
+	}
+	b.WriteString("\n" + muted.Render("enter create • esc back"))
+	// Fill the frame like the lists do
+	return lipgloss.NewStyle().Padding(1, 2).Height(m.list.Height()).Render(b.String())
+}
+
+// pickBaseRef moves to the start point picker for a new branch.
+func (m *model) pickBaseRef(branch string) tea.Cmd {
+	m.pending = addRequest{branch: branch, isNew: true}
+	m.baseDel.editing = false
+	m.refInput.Blur()
+	m.bases.Title = fmt.Sprintf("Start %s from", branch)
+	m.state = stateAddBase
+	return loadRefs
+}
+
+// baseRefItems builds the start point picker: an inline "enter a ref" item, then
+// the default branch and its origin counterpart, HEAD, and every other ref.
+func baseRefItems(refs []git.Ref, def string) []list.Item {
+	labelKind := func(s string) string { return lipgloss.NewStyle().Foreground(theme.Blue).Render(s) }
+	labelDefault := func(s string) string { return lipgloss.NewStyle().Foreground(theme.Green).Render(s) }
+	describe := func(r git.Ref) string {
+		kind := "Branch"
+		switch r.Kind {
+		case git.RefRemote:
+			kind = "Remote"
+		case git.RefTag:
+			kind = "Tag"
+		}
+		return labelKind(kind+":") + " " + r.Subject
+	}
+	items := []list.Item{item{title: addRefLabel, desc: "Branch, tag or commit hash", isAdd: true}}
+	var rest []list.Item
+	for _, r := range refs {
+		it := item{title: r.Name, desc: describe(r), ref: r.Name, remote: r.Kind == git.RefRemote}
+		switch {
+		case def != "" && r.Kind == git.RefBranch && r.Name == def:
+			it.desc = labelDefault("Default branch:") + " " + r.Subject
+			items = append(items, it)
+		case def != "" && r.Kind == git.RefRemote && r.Name == "origin/"+def:
+			it.desc = labelDefault("Default remote branch:") + " " + r.Subject
+			items = append(items, it)
+		default:
+			rest = append(rest, it)
+		}
+	}
+	items = append(items, item{title: "HEAD", desc: labelKind("Current:") + " the commit checked out where this was launched", ref: "HEAD"})
+	return append(items, rest...)
+}
+
+// validateBranchInput refreshes the hint shown under the new branch input.
+func (m *model) validateBranchInput() {
+	m.branchDel.hint, m.branchDel.warn = "", false
+	name, err := m.branchName()
+	switch {
+	case err != nil:
+		m.branchDel.hint = err.Error()
+	case name != "":
+		m.branchDel.hint, m.branchDel.warn = m.branchProblem(name)
+	}
+}
+
+// branchPrefix returns the prefix picked for the new branch, "" for none.
+func (m *model) branchPrefix() string {
+	if m.prefix == 0 || m.prefix > len(m.cfg.BranchPrefixes) {
+		return ""
+	}
+	return m.cfg.BranchPrefixes[m.prefix-1]
+}
+
+// branchName returns the branch name the typed title turns into, "" while
+// nothing is typed.
+func (m *model) branchName() (string, error) {
+	title := strings.TrimSpace(m.input.Value())
+	if title == "" {
+		return "", nil
+	}
+	return m.namer.Name(m.branchPrefix(), title)
+}
+
+// branchInputDesc describes the new branch input: the key cycling prefixes
+// and, when it differs from what was typed, the resulting branch name.
+func (m *model) branchInputDesc() string {
+	var parts []string
+	if len(m.cfg.BranchPrefixes) > 0 {
+		parts = append(parts, keyLabel(m.keys.Prefix)+": change prefix")
+	}
+	if name, err := m.branchName(); err == nil && name != "" && name != m.branchPrefix()+strings.TrimSpace(m.input.Value()) {
+		parts = append(parts, "creates "+name)
+	}
+	if len(parts) == 0 {
+		return addBranchDesc
+	}
+	return strings.Join(parts, " • ")
+}
+
+// branchProblem explains why name can't be used for a new branch, or "" if it
+// can. warn marks problems that don't stop it from being created, such as a
+// worktree path that can still be changed in the path prompt.
+func (m *model) branchProblem(name string) (problem string, warn bool) {
+	if err := git.ValidateBranchName(name); err != nil {
+		return err.Error(), false
+	}
+	if err := m.namer.Check(name); err != nil {
+		return err.Error(), false
+	}
+	for _, li := range m.list.Items() {
+		if it, ok := li.(item); ok && it.wt.BranchName() == name {
+			return fmt.Sprintf("%s is already checked out at %s", name, it.wt.Path), false
+		}
+	}
+	for _, li := range m.branches.Items() {
+		it, ok := li.(item)
+		if !ok || it.isAdd {
+			continue
+		}
+		b := it.br
+		switch {
+		case b.Name == name && b.IsRemote:
+			return fmt.Sprintf("%s exists on %s; pick it from the list to track it", name, b.Remote), true
+		case b.Name == name:
+			return fmt.Sprintf("branch %s already exists; pick it from the list", name), false
+		case !b.IsRemote && (strings.HasPrefix(b.Name, name+"/") || strings.HasPrefix(name, b.Name+"/")):
+			// refs are files, so "a" and "a/b" can't both exist
+			return fmt.Sprintf("%s can't be created while branch %s exists", name, b.Name), false
+		}
+	}
+	if m.root == "" {
+		return "", false
+	}
+	path, err := git.WorktreePathIn(m.root, m.cfg.PathTemplate, name, "")
+	if err != nil {
+		return "", false
+	}
+	// git only accepts a missing or empty directory
+	if fi, err := os.Stat(path); err == nil {
+		if entries, _ := os.ReadDir(path); !fi.IsDir() || len(entries) > 0 {
+			return fmt.Sprintf("%s already exists; change it in the next step", path), true
+		}
+	}
+	return "", false
+}
+
+// setAddItemTitle shows val as the title of a list's synthetic add item (index 0),
+// falling back to label when val is empty and the item isn't being edited.
+func setAddItemTitle(l *list.Model, val, label string, editing bool) {
+	setAddItem(l, val, label, "", editing)
+}
+
+// setAddItem is setAddItemTitle that also replaces the item's description
+// unless desc is empty.
+func setAddItem(l *list.Model, val, label, desc string, editing bool) {
+	items := l.Items()
+	if len(items) == 0 {
+		return
+	}
+	it0, ok := items[0].(item)
+	if !ok || !it0.isAdd {
+		return
+	}
+	title := val
+	if strings.TrimSpace(title) == "" && !editing {
+		title = label
+	}
+	it0.title = title
+	if desc != "" {
+		it0.desc = desc
+	}
+	items[0] = it0
+	setItems(l, items)
+}
+
+// updateBaseItemTitle mirrors the typed ref into the picker's inline item.
+func (m *model) updateBaseItemTitle(val string) {
+	setAddItemTitle(&m.bases, val, addRefLabel, m.baseDel.editing)
+}
@@ -0,0 +1,134 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/fredrikmwold/git-worktree-tui/internal/git"
+	"github.com/fredrikmwold/git-worktree-tui/internal/keymap"
+	"github.com/fredrikmwold/git-worktree-tui/internal/theme"
+)
+
+// maxListedChanges caps how many changed files the force-delete confirmation lists.
+const maxListedChanges = 15
+
+// cancelInlineConfirm restores the list item replaced by the inline delete confirmation.
+func (m *model) cancelInlineConfirm() {
+	if m.confirmIndex == -1 {
+		return
+	}
+	items := m.list.Items()
+	if idx := m.confirmIndex; idx >= 0 && idx < len(items) {
+		items[idx] = m.confirmPrev
+		setItems(&m.list, items)
+	}
+	m.confirmIndex = -1
+}
+
+// removeWorktree attempts a regular (non-forced) removal of wt. If git refuses
+// because the worktree has modified or untracked files, it switches to an explicit
+// force-delete confirmation listing those files instead of discarding them.
+// Locked worktrees are refused up front with a hint to unlock them.
+func (m *model) removeWorktree(wt git.Worktree) tea.Cmd {
+	m.state = stateList
+	if wt.Locked {
+		// A single --force can't remove a locked worktree, so the force prompt would only fail
+		m.pendingBranch = ""
+		msg := fmt.Sprintf("%s is locked; run `git worktree unlock %s` first", filepath.Base(wt.Path), wt.Path)
+		if wt.LockReason != "" {
+			msg = fmt.Sprintf("%s is locked (%s); run `git worktree unlock %s` first", filepath.Base(wt.Path), oneLine(wt.LockReason), wt.Path)
+		}
+		return m.list.NewStatusMessage(msg)
+	}
+	err := git.RemoveWorktree(wt.Path, false)
+	if err == nil {
+		return m.worktreeRemoved(wt)
+	}
+	files, ferr := git.ChangedFiles(wt.Path)
+	if ferr != nil || len(files) == 0 {
+		// Not a dirty-tree refusal (e.g. locked); report git's error as-is
+		return m.list.NewStatusMessage(fmt.Sprintf("Error: %v", err))
+	}
+	m.selected = wt
+	m.confirmMsg = forceDeleteMessage(wt, files, m.keys)
+	m.state = stateConfirmForceDelete
+	return nil
+}
+
+// removeWorktreeAndBranch removes wt like removeWorktree and then deletes its branch.
+func (m *model) removeWorktreeAndBranch(wt git.Worktree) tea.Cmd {
+	m.state = stateList
+	if wt.BranchName() == "" {
+		return m.list.NewStatusMessage(fmt.Sprintf("%s has no branch to delete", filepath.Base(wt.Path)))
+	}
+	m.pendingBranch = wt.BranchName()
+	return m.removeWorktree(wt)
+}
+
+// worktreeRemoved reloads the list and reports a successful removal. If a branch
+// deletion is pending it runs now: merged branches are deleted right away, while
+// unmerged ones need an extra confirmation before forcing (-D). The worktree's
+// multiplexer sessions are killed first when killSessionOnDelete is set.
+func (m *model) worktreeRemoved(wt git.Worktree) tea.Cmd {
+	delete(m.statuses, wt.Path)
+	name := filepath.Base(wt.Path)
+	note := m.killSessions(wt)
+	b := m.pendingBranch
+	if b == "" {
+		return tea.Batch(loadWorktrees, m.list.NewStatusMessage(fmt.Sprintf("Removed worktree %s%s", name, note)))
+	}
+	err := git.DeleteBranch(b, false)
+	switch {
+	case errors.Is(err, git.ErrBranchNotMerged):
+		m.confirmMsg = forceDeleteBranchMessage(b, err, m.keys)
+		m.state = stateConfirmDeleteBranch
+		return loadWorktrees
+	case err != nil:
+		m.pendingBranch = ""
+		return tea.Batch(loadWorktrees, m.list.NewStatusMessage(fmt.Sprintf("Removed worktree %s; branch kept: %v%s", name, err, note)))
+	}
+	m.pendingBranch = ""
+	return tea.Batch(loadWorktrees, m.list.NewStatusMessage(fmt.Sprintf("Removed worktree %s and branch %s%s", name, b, note)))
+}
+
+// forceDeleteBranchMessage builds the confirmation shown before force-deleting an unmerged branch.
+func forceDeleteBranchMessage(branch string, reason error, km keymap.Map) string {
+	title := lipgloss.NewStyle().Foreground(theme.Red).Bold(true)
+	muted := lipgloss.NewStyle().Foreground(theme.Subtext0)
+	var b strings.Builder
+	b.WriteString(title.Render(fmt.Sprintf("Force delete unmerged branch %s?", branch)))
+	b.WriteString("\n\n")
+	b.WriteString("  " + reason.Error() + "\n")
+	b.WriteString("  Its unmerged commits will only be recoverable through the reflog.\n\n")
+	b.WriteString(muted.Render(fmt.Sprintf("Force delete (-D): %s    Keep branch: %s", keyLabel(km.Select), keyLabel(km.Back))))
+	return b.String()
+}
+
+// forceDeleteMessage builds the confirmation shown before force-removing a dirty worktree.
+func forceDeleteMessage(wt git.Worktree, files []string, km keymap.Map) string {
+	title := lipgloss.NewStyle().Foreground(theme.Red).Bold(true)
+	muted := lipgloss.NewStyle().Foreground(theme.Subtext0)
+	name := filepath.Base(wt.Path)
+	noun := "files"
+	if len(files) == 1 {
+		noun = "file"
+	}
+	var b strings.Builder
+	b.WriteString(title.Render(fmt.Sprintf("Force delete %s and lose %d changed %s?", name, len(files), noun)))
+	b.WriteString("\n\n")
+	for i, f := range files {
+		if i == maxListedChanges {
+			b.WriteString(muted.Render(fmt.Sprintf("  … and %d more", len(files)-maxListedChanges)))
+			b.WriteString("\n")
+			break
+		}
+		b.WriteString("  " + f + "\n")
+	}
+	b.WriteString("\n")
+	b.WriteString(muted.Render(fmt.Sprintf("Force delete: %s    Cancel: %s", keyLabel(km.Select), keyLabel(km.Back))))
+	return b.String()
+}
@@ -0,0 +1,57 @@
+package tui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fredrikmwold/git-worktree-tui/internal/git"
+)
+
+type fetchProgressMsg struct{ line string }
+
+type fetchDoneMsg struct{ err error }
+
+// startFetch runs `git fetch --all --prune` in the background. Progress lines and
+// the final result arrive as messages on a channel that waitFor drains one at a time.
+func (m *model) startFetch() tea.Cmd {
+	if m.fetching {
+		return nil
+	}
+	ch := make(chan tea.Msg)
+	go func() {
+		err := git.FetchAll(func(line string) { ch <- fetchProgressMsg{line} })
+		ch <- fetchDoneMsg{err}
+		close(ch)
+	}()
+	m.fetching = true
+	m.fetchCh = ch
+	m.fetchLine = "Fetching remotes…"
+	m.showFetchProgress()
+	return tea.Batch(waitFor(ch), m.spinner.Tick)
+}
+
+// waitFor returns a command that delivers the next message from a background job.
+func waitFor(ch <-chan tea.Msg) tea.Cmd {
+	return func() tea.Msg { return <-ch }
+}
+
+// showFetchProgress renders the spinner and latest progress line into both status bars.
+func (m *model) showFetchProgress() {
+	// The returned timeout commands are dropped on purpose: the message is
+	// replaced on every tick and cleared explicitly when the fetch finishes.
+	s := fmt.Sprintf("%s %s", m.spinner.View(), m.fetchLine)
+	m.list.NewStatusMessage(s)
+	m.branches.NewStatusMessage(s)
+}
+
+// fetchDone reports the fetch result and reloads branches and worktree status.
+func (m *model) fetchDone(err error) tea.Cmd {
+	m.fetching = false
+	m.fetchCh = nil
+	if err != nil {
+		msg := fmt.Sprintf("Error: %v", err)
+		return tea.Batch(m.list.NewStatusMessage(msg), m.branches.NewStatusMessage(msg))
+	}
+	msg := "Fetched all remotes"
+	return tea.Batch(loadBranches, loadWorktrees, m.list.NewStatusMessage(msg), m.branches.NewStatusMessage(msg))
+}
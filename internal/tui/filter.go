@@ -0,0 +1,55 @@
+package tui
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+)
+
+// FilterValue is what the `/` filter matches against: for worktrees the folder
+// name, branch and path separated by tabs, otherwise the title. The name comes
+// first so itemFilter can keep highlights inside the displayed title.
+func (i item) FilterValue() string {
+	if i.wt.Path == "" {
+		return i.title
+	}
+	return filepath.Base(i.wt.Path) + "\t" + i.wt.BranchName() + "\t" + i.wt.Path
+}
+
+// itemFilter fuzzy-matches like list.DefaultFilter, but always keeps the
+// synthetic add item (index 0) on top and only reports matched characters that
+// fall in the leading name, since only that part is shown as the title.
+func itemFilter(term string, targets []string) []list.Rank {
+	if len(targets) == 0 {
+		return nil
+	}
+	ranks := []list.Rank{{Index: 0}}
+	for _, r := range list.DefaultFilter(term, targets) {
+		if r.Index == 0 {
+			continue
+		}
+		name := len(targets[r.Index])
+		if tab := strings.IndexByte(targets[r.Index], '\t'); tab >= 0 {
+			name = tab
+		}
+		var idx []int
+		for _, i := range r.MatchedIndexes {
+			if i < name {
+				idx = append(idx, i)
+			}
+		}
+		r.MatchedIndexes = idx
+		ranks = append(ranks, r)
+	}
+	return ranks
+}
+
+// setItems replaces the items of l, re-applying an active filter right away
+// rather than through the command SetItems returns, so callers need not thread
+// it back through Update.
+func setItems(l *list.Model, items []list.Item) {
+	if cmd := l.SetItems(items); cmd != nil {
+		*l, _ = l.Update(cmd())
+	}
+}
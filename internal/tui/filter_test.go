@@ -0,0 +1,53 @@
+package tui
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/fredrikmwold/git-worktree-tui/internal/git"
+)
+
+func TestItemFilterValue(t *testing.T) {
+	tests := []struct {
+		it   item
+		want string
+	}{
+		{item{title: "[+] Add worktree", isAdd: true}, "[+] Add worktree"},
+		{item{wt: git.Worktree{Path: "/src/app-login", Branch: "refs/heads/feature/login"}}, "app-login\tfeature/login\t/src/app-login"},
+		{item{wt: git.Worktree{Path: "/src/app-hotfix", IsDetached: true}}, "app-hotfix\t\t/src/app-hotfix"},
+	}
+	for _, tt := range tests {
+		if got := tt.it.FilterValue(); got != tt.want {
+			t.Errorf("FilterValue() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestItemFilter(t *testing.T) {
+	targets := []string{
+		"[+] Add worktree",
+		"app\tmain\t/src/app",
+		"app-login\tfeature/login\t/src/app-login",
+		"app-x\tfix/y\t/src/app-x",
+	}
+	tests := []struct {
+		name    string
+		term    string
+		targets []string
+		want    []list.Rank
+	}{
+		{name: "no items", term: "x", targets: nil, want: nil},
+		{name: "no match keeps the add item", term: "zzz", targets: targets, want: []list.Rank{{Index: 0}}},
+		{name: "match in the name is highlighted", term: "login", targets: targets, want: []list.Rank{{Index: 0}, {Index: 2, MatchedIndexes: []int{4, 5, 6, 7, 8}}}},
+		{name: "match in the branch isn't highlighted", term: "fix", targets: targets, want: []list.Rank{{Index: 0}, {Index: 3}}},
+		{name: "add item text doesn't match", term: "worktree", targets: targets, want: []list.Rank{{Index: 0}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := itemFilter(tt.term, tt.targets); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("itemFilter(%q) = %+v, want %+v", tt.term, got, tt.want)
+			}
+		})
+	}
+}
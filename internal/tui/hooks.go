@@ -0,0 +1,90 @@
+package tui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/fredrikmwold/git-worktree-tui/internal/hook"
+	"github.com/fredrikmwold/git-worktree-tui/internal/theme"
+)
+
+// maxHookLines caps how much hook output the log pane keeps.
+const maxHookLines = 2000
+
+type hookOutputMsg struct{ line string }
+
+type hookDoneMsg struct{ err error }
+
+// startHooks runs the post-create hooks in the worktree at path and shows their
+// output in the log pane. Like fetching, output arrives on a channel drained by waitFor.
+func (m *model) startHooks(path string) tea.Cmd {
+	ch := make(chan tea.Msg)
+	commands := m.cfg.PostCreate
+	go func() {
+		err := hook.Run(path, commands, func(line string) { ch <- hookOutputMsg{line} })
+		ch <- hookDoneMsg{err}
+		close(ch)
+	}()
+	m.hooking = true
+	m.hookCh = ch
+	m.hookName = filepath.Base(path)
+	m.hookOut = nil
+	m.hookErr = nil
+	m.hookLog.SetContent("")
+	m.state = stateHooks
+	return tea.Batch(waitFor(ch), m.spinner.Tick)
+}
+
+// appendHookOutput adds a line to the log pane, following the output unless
+// the user scrolled up.
+func (m *model) appendHookOutput(line string) {
+	follow := m.hookLog.AtBottom()
+	m.hookOut = append(m.hookOut, line)
+	if len(m.hookOut) > maxHookLines {
+		m.hookOut = m.hookOut[len(m.hookOut)-maxHookLines:]
+	}
+	m.hookLog.SetContent(strings.Join(m.hookOut, "\n"))
+	if follow {
+		m.hookLog.GotoBottom()
+	}
+}
+
+// hooksDone reports the hook result. A failure keeps the worktree; the log stays
+// open (if still shown) so the output can be read.
+func (m *model) hooksDone(err error) tea.Cmd {
+	m.hooking = false
+	m.hookCh = nil
+	m.hookErr = err
+	if err != nil {
+		return m.list.NewStatusMessage(fmt.Sprintf("Post-create hook failed in %s: %v", m.hookName, err))
+	}
+	return m.list.NewStatusMessage(fmt.Sprintf("Post-create hooks finished in %s", m.hookName))
+}
+
+// hooksView renders the hook log pane.
+func (m model) hooksView() string {
+	title := m.list.Styles.Title.Render("Setting up " + m.hookName)
+	muted := lipgloss.NewStyle().Foreground(theme.Surface2)
+	var status string
+	switch {
+	case m.hooking:
+		status = m.spinner.View() + " Running post-create hooks…"
+	case m.hookErr != nil:
+		status = lipgloss.NewStyle().Foreground(theme.Red).Render("✗ " + m.hookErr.Error() + "; the worktree was kept")
+	default:
+		status = lipgloss.NewStyle().Foreground(theme.Green).Render("✓ Post-create hooks finished")
+	}
+	var b strings.Builder
+	b.WriteString(title + "\n\n")
+	b.WriteString(status + "\n\n")
+	b.WriteString(m.hookLog.View() + "\n\n")
+	help := "↑/↓ scroll • " + m.keys.Back.Help().Key + " back to list"
+	if m.hooking {
+		help += " (hooks keep running)"
+	}
+	b.WriteString(muted.Render(help))
+	return lipgloss.NewStyle().Padding(1, 2).Height(m.list.Height()).Render(b.String())
+}
@@ -4,16 +4,22 @@ import (
 	"fmt"
 	"io"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/fredrikmwold/git-worktree-tui/internal/action"
+	"github.com/fredrikmwold/git-worktree-tui/internal/branchname"
+	"github.com/fredrikmwold/git-worktree-tui/internal/config"
 	"github.com/fredrikmwold/git-worktree-tui/internal/git"
+	"github.com/fredrikmwold/git-worktree-tui/internal/keymap"
+	"github.com/fredrikmwold/git-worktree-tui/internal/mux"
 	"github.com/fredrikmwold/git-worktree-tui/internal/theme"
 )
 
@@ -23,11 +29,14 @@ type item struct {
 	wt    git.Worktree
 	isAdd bool
 	br    git.Branch
+	ref   string // start point offered by the base ref picker
+	act   string // action name in the open action menu
+	// remote marks ref as a remote-tracking branch
+	remote bool
 }
 
 func (i item) Title() string       { return i.title }
 func (i item) Description() string { return i.desc }
-func (i item) FilterValue() string { return i.title }
 
 // states
 
@@ -38,9 +47,25 @@ const (
 	stateAddPick
 	stateAddNewInput
 	stateConfirmDelete
+	stateConfirmForceDelete
+	stateConfirmDeleteBranch
+	stateAddBase
+	stateAddPath
+	stateActions
+	stateHooks
 )
 
+// Options are per-invocation settings for the TUI.
+type Options struct {
+	// CdFile, when set, turns Enter on a worktree into "write its path to CdFile
+	// and quit" so a shell wrapper can cd there.
+	CdFile string
+}
+
 type model struct {
+	cfg        config.Config
+	opts       Options
+	keys       keymap.Map
 	state      state
 	list       list.Model
 	branches   list.Model
@@ -48,9 +73,52 @@ type model struct {
 	confirmMsg string
 	selected   git.Worktree
 	branchDel  *branchDelegate
+	// Why the typed or filtered branch name was rejected; shown on the add item
+	branchErr string
+	// Turns typed titles into branch names; prefix indexes cfg.BranchPrefixes
+	// plus one, 0 meaning no prefix
+	namer  branchname.Namer
+	prefix int
+	// Start point picker for new branches, with its own inline input for custom refs
+	bases    list.Model
+	baseDel  *branchDelegate
+	refInput textinput.Model
+	pending  addRequest
+	// Editable path prompt shown before creating a worktree
+	pathInput textinput.Model
+	pathErr   string
+	pathBack  state // state to return to on Esc
+	// Open actions (built-in plus configured) and the menu listing them
+	actions    []action.Action
+	actionList list.Model
 	// Inline delete confirmation state for main list
 	confirmIndex int // -1 when not confirming; otherwise index in m.list
 	confirmPrev  item
+	// Branch to delete once the selected worktree has been removed ("" for none)
+	pendingBranch string
+	// Main worktree path from the last load, so checks made while typing run no git commands
+	root string
+	// Per-worktree status keyed by path, filled in asynchronously after each load
+	statuses map[string]git.Status
+	// Preview pane next to the worktree list on wide terminals, cached per path
+	split    bool
+	previewW int
+	previewH int
+	previews map[string]preview
+	// Live multiplexer sessions
+	sessions []mux.Session
+	// Background fetch state; progress is streamed through fetchCh
+	fetching  bool
+	fetchCh   <-chan tea.Msg
+	fetchLine string
+	spinner   spinner.Model
+	// Post-create hooks; output is streamed through hookCh into the log pane
+	hooking  bool
+	hookCh   <-chan tea.Msg
+	hookName string
+	hookLog  viewport.Model
+	hookOut  []string
+	hookErr  error
 	// App frame style (rounded mauve border around the entire app)
 	frame lipgloss.Style
 }
@@ -67,9 +135,13 @@ type loadedBranchesMsg struct {
 	err      error
 }
 
-type editorDoneMsg struct{ err error }
+type loadedStatusMsg struct {
+	path   string
+	status git.Status
+	err    error
+}
 
-func initialModel() model {
+func initialModel(cfg config.Config, opts Options) model {
 	// Main worktree list with default delegate (built-in indicator)
 	mainDel := list.NewDefaultDelegate()
 	applyDelegateTheme(&mainDel)
@@ -77,36 +149,44 @@ func initialModel() model {
 	li.Title = "Git Worktrees"
 	li.SetShowStatusBar(true)
 	li.SetShowPagination(true)
-	li.SetFilteringEnabled(false)
-	li.SetShowFilter(false)
+	li.SetFilteringEnabled(true)
+	li.SetShowFilter(true)
+	li.Filter = itemFilter
 	li.SetShowHelp(true)
 	li.SetShowTitle(true)
 	applyListTheme(&li)
+	// Help footers are built from the same bindings Update matches against
+	km := cfg.Keys
+	if cfg.StayAfterEditor {
+		km.AltEdit = withHelp(km.AltEdit, "edit & quit")
+	}
+	li.KeyMap.Quit = km.Quit
 	li.AdditionalShortHelpKeys = func() []key.Binding {
-		return []key.Binding{
-			key.NewBinding(key.WithKeys("a"), key.WithHelp("a", "add")),
-			key.NewBinding(key.WithKeys("d"), key.WithHelp("d", "delete")),
-			key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "refresh")),
-		}
+		return []key.Binding{km.Add, km.OpenWith, km.Delete, km.Refresh, km.Fetch}
 	}
 	li.AdditionalFullHelpKeys = func() []key.Binding {
-		return []key.Binding{
-			key.NewBinding(key.WithKeys("a"), key.WithHelp("a", "add")),
-			key.NewBinding(key.WithKeys("d"), key.WithHelp("d", "delete")),
-			key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "refresh")),
-		}
+		return []key.Binding{km.Add, km.OpenWith, km.AltEdit, km.Delete, km.Refresh, km.Fetch}
 	}
 
 	// Create the model and input before wiring delegate so the delegate can point to m.input
 	in := textinput.New()
 	in.Placeholder = "new-branch-name"
-	in.CharLimit = 64
+	in.CharLimit = 255
 	in.Prompt = ""
 	in.TextStyle = lipgloss.NewStyle().Foreground(theme.Text)
 	in.PlaceholderStyle = lipgloss.NewStyle().Foreground(theme.Surface2)
 	in.Cursor.Style = lipgloss.NewStyle().Foreground(theme.Mauve)
 
-	m := model{state: stateList, list: li, input: in, confirmIndex: -1}
+	m := model{cfg: cfg, opts: opts, keys: km, state: stateList, list: li, input: in, confirmIndex: -1, statuses: map[string]git.Status{}, previews: map[string]preview{}}
+	m.hookLog = viewport.New(0, 0)
+	if len(cfg.Untrusted) > 0 {
+		// Stays up until another status message replaces it
+		m.list.NewStatusMessage(fmt.Sprintf("Ignored %s from the untrusted %s; see `worktree-tui config`",
+			strings.Join(cfg.Untrusted, ", "), config.RepoFileName))
+	}
+	// Already validated by config.Load
+	m.namer, _ = branchname.New(cfg.BranchTemplate, cfg.BranchPattern)
+	m.spinner = spinner.New(spinner.WithSpinner(spinner.Dot), spinner.WithStyle(lipgloss.NewStyle().Foreground(theme.Mauve)))
 
 	// Create a rounded mauve border frame for the whole app
 	m.frame = lipgloss.NewStyle().
@@ -122,48 +202,137 @@ func initialModel() model {
 	br.SetShowStatusBar(true)
 	br.SetShowPagination(true)
 	br.SetShowHelp(true)
-	br.SetFilteringEnabled(false)
+	br.SetFilteringEnabled(true)
+	br.Filter = itemFilter
 	br.SetShowTitle(true)
 	applyListTheme(&br)
-	br.AdditionalShortHelpKeys = func() []key.Binding {
-		return []key.Binding{
-			key.NewBinding(key.WithKeys("n"), key.WithHelp("n", "new branch")),
-			key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "select/create")),
-			key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "back/cancel")),
-		}
-	}
-	br.AdditionalFullHelpKeys = func() []key.Binding {
-		return []key.Binding{
-			key.NewBinding(key.WithKeys("n"), key.WithHelp("n", "new branch")),
-			key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "select/create")),
-			key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "back/cancel")),
-		}
+	br.KeyMap.Quit = km.Quit
+	brKeys := func() []key.Binding {
+		return []key.Binding{km.NewBranch, km.Fetch, withHelp(km.Select, "select/create"), withHelp(km.Back, "back/cancel")}
 	}
+	br.AdditionalShortHelpKeys = brKeys
+	br.AdditionalFullHelpKeys = brKeys
 
 	m.branches = br
 	m.branchDel = del
+
+	// Start point picker shown after naming a new branch
+	m.refInput = in
+	m.refInput.Placeholder = "ref or commit"
+	baseBase := list.NewDefaultDelegate()
+	applyDelegateTheme(&baseBase)
+	m.baseDel = &branchDelegate{base: baseBase, input: &m.refInput}
+	bl := list.New([]list.Item{}, m.baseDel, 0, 0)
+	bl.Title = "Start from"
+	bl.SetShowStatusBar(true)
+	bl.SetShowPagination(true)
+	bl.SetShowHelp(true)
+	bl.SetFilteringEnabled(false)
+	bl.SetShowTitle(true)
+	bl.SetStatusBarItemName("ref", "refs")
+	applyListTheme(&bl)
+	bl.KeyMap.Quit = km.Quit
+	baseKeys := func() []key.Binding {
+		return []key.Binding{withHelp(km.Select, "create from ref"), km.Back}
+	}
+	bl.AdditionalShortHelpKeys = baseKeys
+	bl.AdditionalFullHelpKeys = baseKeys
+	m.bases = bl
+
+	m.pathInput = in
+	m.pathInput.Placeholder = "/path/to/worktree"
+	m.pathInput.CharLimit = 0
+
+	// Open action menu
+	m.actions = action.Merge(action.Builtins(cfg.Editor), cfg.Actions)
+	if cfg.StayAfterEditor {
+		for i := range m.actions {
+			if m.actions[i].Name == "editor" && m.actions[i].Mode == action.ModeQuit {
+				m.actions[i].Mode = action.ModeStay
+			}
+		}
+	}
+	actDel := list.NewDefaultDelegate()
+	applyDelegateTheme(&actDel)
+	al := list.New([]list.Item{}, actDel, 0, 0)
+	al.SetShowStatusBar(false)
+	al.SetShowHelp(true)
+	al.SetFilteringEnabled(false)
+	al.SetShowTitle(true)
+	applyListTheme(&al)
+	al.KeyMap.Quit = km.Quit
+	actKeys := func() []key.Binding {
+		return []key.Binding{withHelp(km.Select, "run"), km.Back}
+	}
+	al.AdditionalShortHelpKeys = actKeys
+	al.AdditionalFullHelpKeys = actKeys
+	m.actionList = al
 	return m
 }
 
+// withHelp returns b with a view-specific help description.
+func withHelp(b key.Binding, desc string) key.Binding {
+	b.SetHelp(b.Help().Key, desc)
+	return b
+}
+
+// keyLabel names a binding's first key for inline prompts, e.g. "Enter" or "x".
+func keyLabel(b key.Binding) string {
+	ks := b.Keys()
+	if len(ks) == 0 {
+		return ""
+	}
+	k := ks[0]
+	if len(k) > 1 && !strings.Contains(k, "+") {
+		// Named keys read better capitalized: Enter, Esc, Delete
+		k = strings.ToUpper(k[:1]) + k[1:]
+	}
+	return k
+}
+
 // applyListTheme applies app-wide colors to list chrome using the theme palette.
 func applyListTheme(l *list.Model) {
 	s := l.Styles
-	// Title with Lavender background and dark text; leave everything else as defaults
+	// Title with Lavender background and dark text
 	s.Title = s.Title.Background(theme.Lavender).Foreground(theme.Crust).Bold(true)
+	s.StatusBar = s.StatusBar.Foreground(theme.Subtext0)
+	s.StatusEmpty = s.StatusEmpty.Foreground(theme.Surface2)
+	s.StatusBarActiveFilter = s.StatusBarActiveFilter.Foreground(theme.Text)
+	s.StatusBarFilterCount = s.StatusBarFilterCount.Foreground(theme.Surface2)
+	s.NoItems = s.NoItems.Foreground(theme.Surface2)
+	s.FilterPrompt = s.FilterPrompt.Foreground(theme.Green)
+	s.FilterCursor = s.FilterCursor.Foreground(theme.Mauve)
+	s.ActivePaginationDot = s.ActivePaginationDot.Foreground(theme.Subtext0)
+	s.InactivePaginationDot = s.InactivePaginationDot.Foreground(theme.Surface1)
+	s.DividerDot = s.DividerDot.Foreground(theme.Surface1)
 	l.Styles = s
+	l.FilterInput.PromptStyle = s.FilterPrompt
+	l.FilterInput.Cursor.Style = s.FilterCursor
+	h := l.Help.Styles
+	h.ShortKey = h.ShortKey.Foreground(theme.Subtext0)
+	h.ShortDesc = h.ShortDesc.Foreground(theme.Surface2)
+	h.ShortSeparator = h.ShortSeparator.Foreground(theme.Surface1)
+	h.FullKey = h.FullKey.Foreground(theme.Subtext0)
+	h.FullDesc = h.FullDesc.Foreground(theme.Surface2)
+	h.FullSeparator = h.FullSeparator.Foreground(theme.Surface1)
+	h.Ellipsis = h.Ellipsis.Foreground(theme.Surface1)
+	l.Help.Styles = h
 }
 
-// applyDelegateTheme styles the default list item delegate for normal and selected states.
+// applyDelegateTheme colors list items using the theme palette.
 func applyDelegateTheme(d *list.DefaultDelegate) {
 	st := d.Styles
 	// Normal item titles use theme Text color
 	st.NormalTitle = st.NormalTitle.Foreground(theme.Text)
 	st.NormalDesc = st.NormalDesc.Foreground(theme.Surface1)
-	st.SelectedDesc = st.SelectedDesc.Foreground(theme.Surface1)
 	// Selected item: color only the left indicator (border) Mauve
 	st.SelectedTitle = st.SelectedTitle.BorderLeftForeground(theme.Mauve).Foreground(theme.Mauve)
 	// Color only the selected description's indicator (border) Mauve; leave text color default
 	st.SelectedDesc = st.SelectedDesc.Foreground(theme.Surface1).BorderLeftForeground(theme.Mauve)
+	// Items dimmed while typing a filter
+	st.DimmedTitle = st.DimmedTitle.Foreground(theme.Surface2)
+	st.DimmedDesc = st.DimmedDesc.Foreground(theme.Surface1)
+	st.FilterMatch = st.FilterMatch.Underline(true).Foreground(theme.Peach)
 	d.Styles = st
 }
 
@@ -172,18 +341,174 @@ type branchDelegate struct {
 	base    list.DefaultDelegate
 	input   *textinput.Model
 	editing bool
+	// Validation message shown under the input while editing; warn marks it as non-blocking
+	hint string
+	warn bool
 }
 
 func (d *branchDelegate) Height() int                               { return d.base.Height() }
 func (d *branchDelegate) Spacing() int                              { return d.base.Spacing() }
 func (d *branchDelegate) Update(msg tea.Msg, m *list.Model) tea.Cmd { return d.base.Update(msg, m) }
 func (d *branchDelegate) Render(w io.Writer, m list.Model, index int, listItem list.Item) {
+	if it, ok := listItem.(item); ok && it.isAdd && d.editing && d.hint != "" {
+		color := theme.Red
+		if d.warn {
+			color = theme.Yellow
+		}
+		it.desc = lipgloss.NewStyle().Foreground(color).Render(d.hint)
+		listItem = it
+	}
 	// Use built-in delegate rendering (indicator on title line only)
 	d.base.Render(w, m, index, listItem)
 }
 
-func NewProgram() *tea.Program {
-	m := initialModel()
+// itemFor builds the list item for wt from the status and sessions known so far.
+func (m model) itemFor(wt git.Worktree) item {
+	return worktreeItem(wt, m.status(wt.Path), m.liveSessions(wt.Path))
+}
+
+// refreshItem rebuilds the list item for the worktree at path.
+func (m *model) refreshItem(path string) {
+	items := m.list.Items()
+	for i, li := range items {
+		it, ok := li.(item)
+		if !ok || it.isAdd || it.wt.Path != path {
+			continue
+		}
+		updated := m.itemFor(it.wt)
+		if i == m.confirmIndex {
+			// Keep the confirmation visible; refresh what Esc restores
+			m.confirmPrev = updated
+		} else {
+			items[i] = updated
+			setItems(&m.list, items)
+		}
+		return
+	}
+}
+
+// status returns the last known status for the worktree at path, or nil if not loaded yet.
+func (m model) status(path string) *git.Status {
+	if st, ok := m.statuses[path]; ok {
+		return &st
+	}
+	return nil
+}
+
+// worktreeItem builds the list item for a worktree: folder name plus state badges
+// as the title, labeled branch/status/path info (and lock/prune reasons) as the description.
+// st may be nil while the status is still loading; sessions lists the multiplexers
+// with a live session for the worktree.
+func worktreeItem(wt git.Worktree, st *git.Status, sessions []mux.Kind) item {
+	// Use varied accents for labels to add visual distinction
+	labelBranch := func(s string) string { return lipgloss.NewStyle().Foreground(theme.Sky).Render(s) }
+	labelPath := func(s string) string { return lipgloss.NewStyle().Foreground(theme.Green).Render(s) }
+	labelWarn := func(s string) string { return lipgloss.NewStyle().Foreground(theme.Yellow).Render(s) }
+	labelStatus := func(s string) string { return lipgloss.NewStyle().Foreground(theme.Lavender).Render(s) }
+	value := func(s string) string { return s }
+	branch := wt.Branch
+	if branch == "" && !wt.IsBare {
+		branch = git.ShortHash(wt.HEAD)
+	}
+	// Show just the branch name (strip common refs prefixes)
+	if strings.HasPrefix(branch, "refs/heads/") {
+		branch = strings.TrimPrefix(branch, "refs/heads/")
+	} else if strings.HasPrefix(branch, "heads/") {
+		branch = strings.TrimPrefix(branch, "heads/")
+	} else if strings.HasPrefix(branch, "refs/") {
+		branch = strings.TrimPrefix(branch, "refs/")
+	}
+	// Title: just the name of the worktree (folder name) followed by badges
+	t := filepath.Base(wt.Path)
+	if badges := worktreeBadges(wt, sessions); badges != "" {
+		t += " " + badges
+	}
+	// Desc: labeled info segments
+	var segs []string
+	if branch != "" {
+		segs = append(segs, labelBranch("Branch:")+" "+value(branch))
+	}
+	if st != nil {
+		segs = append(segs, labelStatus("Status:")+" "+statusSummary(*st))
+	}
+	segs = append(segs, labelPath("Path:")+" "+value(wt.Path))
+	if wt.Locked && wt.LockReason != "" {
+		segs = append(segs, labelWarn("Locked:")+" "+value(oneLine(wt.LockReason)))
+	}
+	if wt.Prunable && wt.PruneReason != "" {
+		segs = append(segs, labelWarn("Prunable:")+" "+value(oneLine(wt.PruneReason)))
+	}
+	return item{title: t, desc: strings.Join(segs, "  "), wt: wt}
+}
+
+// worktreeBadges renders the short state markers shown after a worktree's name.
+func worktreeBadges(wt git.Worktree, sessions []mux.Kind) string {
+	badge := func(c lipgloss.Color, s string) string {
+		return lipgloss.NewStyle().Foreground(c).Render("[" + s + "]")
+	}
+	var bs []string
+	if wt.IsMain {
+		bs = append(bs, badge(theme.Blue, "main"))
+	}
+	if wt.IsBare {
+		bs = append(bs, badge(theme.Subtext0, "bare"))
+	}
+	if wt.IsDetached {
+		bs = append(bs, badge(theme.Peach, "detached"))
+	}
+	if wt.Locked {
+		bs = append(bs, badge(theme.Yellow, "locked"))
+	}
+	if wt.Prunable {
+		bs = append(bs, badge(theme.Red, "prunable"))
+	}
+	for _, k := range sessions {
+		bs = append(bs, badge(theme.Green, string(k)))
+	}
+	return strings.Join(bs, " ")
+}
+
+// statusSummary renders compact change and ahead/behind counts,
+// e.g. "+2 ~1 ?3 ↑1 ↓4" or "clean".
+func statusSummary(st git.Status) string {
+	seg := func(c lipgloss.Color, s string, n int) string {
+		return lipgloss.NewStyle().Foreground(c).Render(fmt.Sprintf("%s%d", s, n))
+	}
+	var parts []string
+	if st.Conflicted > 0 {
+		parts = append(parts, seg(theme.Red, "!", st.Conflicted))
+	}
+	if st.Staged > 0 {
+		parts = append(parts, seg(theme.Green, "+", st.Staged))
+	}
+	if st.Unstaged > 0 {
+		parts = append(parts, seg(theme.Peach, "~", st.Unstaged))
+	}
+	if st.Untracked > 0 {
+		parts = append(parts, seg(theme.Subtext0, "?", st.Untracked))
+	}
+	if !st.Dirty() {
+		parts = append(parts, lipgloss.NewStyle().Foreground(theme.Green).Render("clean"))
+	}
+	if st.Upstream != "" {
+		if st.Ahead > 0 {
+			parts = append(parts, seg(theme.Blue, "↑", st.Ahead))
+		}
+		if st.Behind > 0 {
+			parts = append(parts, seg(theme.Sky, "↓", st.Behind))
+		}
+		if st.Ahead == 0 && st.Behind == 0 {
+			parts = append(parts, lipgloss.NewStyle().Foreground(theme.Subtext0).Render("="))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// oneLine collapses multi-line text (e.g. lock reasons) onto a single line.
+func oneLine(s string) string { return strings.Join(strings.Fields(s), " ") }
+
+func NewProgram(cfg config.Config, opts Options) *tea.Program {
+	m := initialModel(cfg, opts)
 	return tea.NewProgram(m)
 }
 
@@ -196,6 +521,14 @@ func loadWorktrees() tea.Msg {
 	return loadedWorktreesMsg{wts: wts, err: err}
 }
 
+// loadStatus returns a command computing the status of a single worktree.
+func loadStatus(path string) tea.Cmd {
+	return func() tea.Msg {
+		st, err := git.WorktreeStatus(path)
+		return loadedStatusMsg{path: path, status: st, err: err}
+	}
+}
+
 func loadBranches() tea.Msg {
 	brs, err := git.ListBranchesDetailed()
 	return loadedBranchesMsg{branches: brs, err: err}
@@ -221,67 +554,109 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if innerH < 0 {
 			innerH = 0
 		}
-		m.list.SetSize(innerW, innerH)
+		m.layoutPreview(innerW, innerH)
 		m.branches.SetSize(innerW, innerH)
+		m.bases.SetSize(innerW, innerH)
+		m.actionList.SetSize(innerW, innerH)
 		// Size the inline editor to fit the list content width with a small margin
 		w := innerW - 6
 		if w < 10 {
 			w = 10
 		}
 		m.input.Width = w
+		m.refInput.Width = w
+		m.pathInput.Width = w
+		// Hook log: the frame minus padding, title, status and help lines
+		m.hookLog.Width = max(innerW-4, 0)
+		m.hookLog.Height = max(innerH-8, 1)
 
 		// Help line wrapping control: always show help; use short vs full based on width and constrain width
 		m.list.SetShowHelp(true)
 		m.branches.SetShowHelp(true)
 		// Constrain help style width to inner content width to avoid wrapping
-		ls := m.list.Styles
-		ls.HelpStyle = ls.HelpStyle.Foreground(theme.Surface2).MaxWidth(innerW)
-		m.list.Styles = ls
 		bs := m.branches.Styles
 		bs.HelpStyle = bs.HelpStyle.Foreground(theme.Surface2).MaxWidth(innerW)
 		m.branches.Styles = bs
-		return m, nil
-	case editorDoneMsg:
-		// Exit the app after the editor process completes
-		return m, tea.Quit
+		rs := m.bases.Styles
+		rs.HelpStyle = rs.HelpStyle.Foreground(theme.Surface2).MaxWidth(innerW)
+		m.bases.Styles = rs
+		as := m.actionList.Styles
+		as.HelpStyle = as.HelpStyle.Foreground(theme.Surface2).MaxWidth(innerW)
+		m.actionList.Styles = as
+		return m, m.loadSelectedPreview()
+	case spinner.TickMsg:
+		if !m.fetching && !m.hooking {
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		if m.fetching {
+			m.showFetchProgress()
+		}
+		return m, cmd
+	case list.FilterMatchesMsg:
+		// Results of filter text typed into whichever list is showing
+		l := m.activeList()
+		var cmd tea.Cmd
+		*l, cmd = l.Update(msg)
+		return m, tea.Batch(cmd, m.loadSelectedPreview())
+	case fetchProgressMsg:
+		m.fetchLine = msg.line
+		m.showFetchProgress()
+		return m, waitFor(m.fetchCh)
+	case fetchDoneMsg:
+		return m, m.fetchDone(msg.err)
+	case hookOutputMsg:
+		m.appendHookOutput(msg.line)
+		return m, waitFor(m.hookCh)
+	case hookDoneMsg:
+		return m, m.hooksDone(msg.err)
+	case actionDoneMsg:
+		return m, m.actionDone(msg)
 	case loadedWorktreesMsg:
 		if msg.err != nil {
 			return m, m.list.NewStatusMessage(fmt.Sprintf("Error: %v", msg.err))
 		}
+		// git lists the main worktree first
+		if len(msg.wts) > 0 && filepath.IsAbs(msg.wts[0].Path) {
+			m.root = filepath.Clean(msg.wts[0].Path)
+		}
 		items := make([]list.Item, 0, len(msg.wts)+1)
 		// Prepend an inline action to add a new worktree
 		items = append(items, item{title: "[+] Add new worktree", desc: "Create from existing or new branch", isAdd: true})
-		// Use varied accents for labels to add visual distinction
-		labelBranch := func(s string) string { return lipgloss.NewStyle().Foreground(theme.Sky).Render(s) }
-		labelPath := func(s string) string { return lipgloss.NewStyle().Foreground(theme.Green).Render(s) }
-		value := func(s string) string { return s }
+		// Render immediately with any previously known status, then refresh each one in the background
+		var cmds []tea.Cmd
 		for _, wt := range msg.wts {
-			branch := wt.Branch
-			if branch == "" {
-				branch = wt.HEAD
-			}
-			// Show just the branch name (strip common refs prefixes)
-			if strings.HasPrefix(branch, "refs/heads/") {
-				branch = strings.TrimPrefix(branch, "refs/heads/")
-			} else if strings.HasPrefix(branch, "heads/") {
-				branch = strings.TrimPrefix(branch, "heads/")
-			} else if strings.HasPrefix(branch, "refs/") {
-				branch = strings.TrimPrefix(branch, "refs/")
+			items = append(items, m.itemFor(wt))
+			if !wt.IsBare && !wt.Prunable {
+				cmds = append(cmds, loadStatus(wt.Path))
 			}
-			// Title: just the name of the worktree (folder name)
-			t := filepath.Base(wt.Path)
-			// Desc: labeled info segments
-			var segs []string
-			if branch != "" {
-				segs = append(segs, labelBranch("Branch:")+" "+value(branch))
-			}
-			segs = append(segs, labelPath("Path:")+" "+value(wt.Path))
-			d := strings.Join(segs, "  ")
-			items = append(items, item{title: t, desc: d, wt: wt})
 		}
-		m.list.SetItems(items)
+		setItems(&m.list, items)
 		// Clear any pending inline delete confirmation
 		m.confirmIndex = -1
+		// Previews may be stale after a refresh, create or delete
+		m.previews = map[string]preview{}
+		cmds = append(cmds, loadSessions, m.loadSelectedPreview())
+		return m, tea.Batch(cmds...)
+	case loadedStatusMsg:
+		if msg.err != nil {
+			// Leave the item as-is; status is best effort
+			return m, nil
+		}
+		m.statuses[msg.path] = msg.status
+		m.refreshItem(msg.path)
+		return m, nil
+	case loadedPreviewMsg:
+		m.previews[msg.path] = msg.preview
+		return m, nil
+	case loadedSessionsMsg:
+		m.sessions = msg.sessions
+		for _, li := range m.list.Items() {
+			if it, ok := li.(item); ok && !it.isAdd {
+				m.refreshItem(it.wt.Path)
+			}
+		}
 		return m, nil
 	case loadedBranchesMsg:
 		if msg.err != nil {
@@ -290,12 +665,14 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		items := make([]list.Item, 0, len(msg.branches)+1)
 		labelTrack := func(s string) string { return lipgloss.NewStyle().Foreground(theme.Blue).Render(s) }
 		labelMuted := func(s string) string { return lipgloss.NewStyle().Foreground(theme.Surface1).Render(s) }
+		labelRemote := func(s string) string { return lipgloss.NewStyle().Foreground(theme.Peach).Render(s) }
 		value := func(s string) string { return s }
 		// Prepend synthetic option to create a new branch
-		items = append(items, item{title: "[+] Create new branch", desc: "Type a new branch name", isAdd: true})
+		items = append(items, item{title: addBranchLabel, desc: addBranchDesc, isAdd: true})
 		for _, b := range msg.branches {
-			// Title: branch name; Desc: show tracking info for locals; gray 'no remote' if none
-			desc := ""
+			// Title: branch name; Desc: show tracking info for locals; gray 'no remote' if none;
+			// the remote ref for remote-only branches
+			desc := labelRemote("Remote:") + " " + value(b.RemoteRef)
 			if !b.IsRemote {
 				up := strings.TrimSpace(b.Upstream)
 				if up == "" {
@@ -306,7 +683,17 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			items = append(items, item{title: b.Name, desc: desc, br: b})
 		}
-		m.branches.SetItems(items)
+		setItems(&m.branches, items)
+		// A filter typed before the branches arrived may now name an existing one
+		m.updateAddItemTitle(m.input.Value())
+		return m, nil
+	case loadedRefsMsg:
+		if msg.err != nil {
+			return m, m.bases.NewStatusMessage(fmt.Sprintf("Error: %v", msg.err))
+		}
+		m.bases.SetItems(baseRefItems(msg.refs, msg.def))
+		// Preselect the first real ref (the default branch when known)
+		m.bases.Select(1)
 		return m, nil
 	case tea.KeyMsg:
 		k := msg.String()
@@ -316,64 +703,76 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		switch m.state {
 		case stateList:
-			switch k {
-			case "q", "ctrl+c":
+			if m.list.SettingFilter() {
+				// Typing a filter: every key belongs to the filter input
+				var cmd tea.Cmd
+				m.list, cmd = m.list.Update(msg)
+				return m, tea.Batch(cmd, m.loadSelectedPreview())
+			}
+			switch {
+			case key.Matches(msg, m.keys.Quit):
 				return m, tea.Quit
-			case "esc":
-				// Cancel inline delete confirmation if active
+			case key.Matches(msg, m.keys.Back):
+				// Cancel inline delete confirmation if active, otherwise clear the filter
 				if m.confirmIndex != -1 {
-					// restore previous item content
-					items := m.list.Items()
-					if idx := m.confirmIndex; idx >= 0 && idx < len(items) {
-						items[idx] = m.confirmPrev
-						m.list.SetItems(items)
-					}
-					m.confirmIndex = -1
-					return m, nil
+					m.cancelInlineConfirm()
+				} else if m.list.IsFiltered() {
+					m.list.ResetFilter()
 				}
 				return m, nil
-			case "r":
+			case key.Matches(msg, m.keys.Refresh):
 				m.confirmIndex = -1
 				return m, loadWorktrees
-			case "a":
-				m.state = stateAddPick
-				return m, loadBranches
-			case "enter":
+			case key.Matches(msg, m.keys.Fetch):
+				return m, m.startFetch()
+			case key.Matches(msg, m.keys.Add):
+				return m, m.openAddPicker()
+			case key.Matches(msg, m.keys.Select):
 				// If confirming delete inline, Enter = Yes
-				if m.confirmIndex != -1 && m.list.Index() == m.confirmIndex {
+				if m.confirmIndex != -1 && m.list.GlobalIndex() == m.confirmIndex {
+					m.cancelInlineConfirm()
 					if m.selected.Path != "" {
-						if err := git.RemoveWorktree(m.selected.Path, true); err != nil {
-							// restore and show error
-							items := m.list.Items()
-							if idx := m.confirmIndex; idx >= 0 && idx < len(items) {
-								items[idx] = m.confirmPrev
-								m.list.SetItems(items)
-							}
-							m.confirmIndex = -1
-							return m, m.list.NewStatusMessage(fmt.Sprintf("Error: %v", err))
-						}
-						// cleared by loadWorktrees
-						m.confirmIndex = -1
-						name := filepath.Base(m.selected.Path)
-						return m, tea.Batch(loadWorktrees, m.list.NewStatusMessage(fmt.Sprintf("Removed worktree %s", name)))
+						m.pendingBranch = ""
+						return m, m.removeWorktree(m.selected)
 					}
 					return m, nil
 				}
 				if it, ok := m.list.SelectedItem().(item); ok {
 					if it.isAdd {
-						m.state = stateAddPick
-						return m, loadBranches
+						return m, m.openAddPicker()
 					}
-					if it.wt.Path != "" {
-						cmd, err := buildEditorCmd(it.wt.Path)
-						if err != nil {
-							return m, m.list.NewStatusMessage(fmt.Sprintf("No editor: %v", err))
+					if it.wt.Path != "" && m.opts.CdFile != "" {
+						// Shell integration: hand the path to the wrapper function and exit
+						if err := os.WriteFile(m.opts.CdFile, []byte(it.wt.Path), 0o600); err != nil {
+							return m, m.list.NewStatusMessage(fmt.Sprintf("Error: %v", err))
 						}
-						return m, tea.ExecProcess(cmd, func(err error) tea.Msg { return editorDoneMsg{err} })
+						return m, tea.Quit
 					}
+					if it.wt.Path != "" {
+						return m, m.runAction("editor", it.wt)
+					}
+				}
+				return m, nil
+			case key.Matches(msg, m.keys.DeleteBranch):
+				// Inline confirmation: delete the worktree and its branch too
+				if m.confirmIndex != -1 && m.list.GlobalIndex() == m.confirmIndex {
+					m.cancelInlineConfirm()
+					return m, m.removeWorktreeAndBranch(m.selected)
+				}
+			case key.Matches(msg, m.keys.AltEdit):
+				// Open in the editor, flipping whether we return to the list afterwards
+				if it, ok := m.list.SelectedItem().(item); ok && !it.isAdd && it.wt.Path != "" {
+					m.cancelInlineConfirm()
+					return m, m.runActionWith("editor", it.wt, true)
+				}
+				return m, nil
+			case key.Matches(msg, m.keys.OpenWith):
+				if it, ok := m.list.SelectedItem().(item); ok && !it.isAdd && it.wt.Path != "" && !it.wt.IsBare {
+					m.cancelInlineConfirm()
+					m.openActionMenu(it.wt)
 				}
 				return m, nil
-			case "d":
+			case key.Matches(msg, m.keys.Delete):
 				if it, ok := m.list.SelectedItem().(item); ok {
 					if it.isAdd {
 						return m, nil
@@ -382,98 +781,129 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						return m, m.list.NewStatusMessage("Cannot delete main worktree")
 					}
 					// If another confirmation is active, restore it first
-					if m.confirmIndex != -1 {
-						items := m.list.Items()
-						if idx := m.confirmIndex; idx >= 0 && idx < len(items) {
-							items[idx] = m.confirmPrev
-							m.list.SetItems(items)
-						}
-						m.confirmIndex = -1
-					}
+					m.cancelInlineConfirm()
 					m.selected = it.wt
 					// Mutate the selected list item to show inline confirmation
-					idx := m.list.Index()
+					idx := m.list.GlobalIndex()
 					m.confirmIndex = idx
 					m.confirmPrev = it
 					items := m.list.Items()
 					// Build confirmation text on title; keep description for Yes/No
 					confirmItem := it
-					confirmItem.title = fmt.Sprintf("Are you sure you want to delete: %s", it.title)
-					confirmItem.desc = "Yes: Enter    No: Esc"
+					confirmItem.title = fmt.Sprintf("Are you sure you want to delete: %s", filepath.Base(it.wt.Path))
+					confirmItem.desc = fmt.Sprintf("Yes: %s    Yes + delete branch: %s    No: %s",
+						keyLabel(m.keys.Select), keyLabel(m.keys.DeleteBranch), keyLabel(m.keys.Back))
 					items[idx] = confirmItem
-					m.list.SetItems(items)
+					setItems(&m.list, items)
 				}
 				return m, nil
 			}
 			var cmd tea.Cmd
 			m.list, cmd = m.list.Update(msg)
-			return m, cmd
+			// The cursor may have moved to a worktree not previewed yet
+			return m, tea.Batch(cmd, m.loadSelectedPreview())
 		case stateAddPick:
 			// Inline editing mode for the "Create new branch" synthetic item
 			if m.branchDel != nil && m.branchDel.editing {
+				if len(m.cfg.BranchPrefixes) > 0 && key.Matches(msg, m.keys.Prefix) {
+					m.prefix = (m.prefix + 1) % (len(m.cfg.BranchPrefixes) + 1)
+					m.validateBranchInput()
+					m.updateAddItemTitle(m.input.Value())
+					return m, nil
+				}
 				switch k {
 				case "esc":
 					m.branchDel.editing = false
 					m.input.Blur()
+					m.branchErr = ""
+					m.branchDel.hint = ""
 					// reset the add item title
 					m.resetAddItemTitle()
 					return m, nil
 				case "enter":
-					branch := strings.TrimSpace(m.input.Value())
-					if branch == "" {
+					branch, err := m.branchName()
+					if branch == "" || err != nil || (m.branchDel.hint != "" && !m.branchDel.warn) {
 						return m, nil
 					}
-					path := git.DefaultWorktreeDir(branch)
-					if err := git.CreateWorktree(branch, path, true); err != nil {
-						return m, m.branches.NewStatusMessage(fmt.Sprintf("Error: %v", err))
+					if !m.checkBranchName(branch) {
+						return m, nil
 					}
 					m.branchDel.editing = false
 					m.input.Blur()
 					m.resetAddItemTitle()
-					m.state = stateList
-					name := filepath.Base(path)
-					return m, tea.Batch(loadWorktrees, m.list.NewStatusMessage(fmt.Sprintf("Created worktree %s", name)))
+					// Next step: choose where the new branch starts
+					return m, m.pickBaseRef(branch)
 				}
 				var cmd tea.Cmd
 				m.input, cmd = m.input.Update(msg)
+				m.branchErr = ""
+				m.validateBranchInput()
 				// update visible text in the first item
 				m.updateAddItemTitle(m.input.Value())
 				return m, cmd
 			}
-			switch k {
-			case "esc":
+			if m.branches.SettingFilter() {
+				// Enter with nothing but the create item left goes straight to creating it
+				if k == "enter" && m.createQuery() != "" && len(m.branches.VisibleItems()) == 1 {
+					return m, m.createFromFilter()
+				}
+				before := m.branches.FilterValue()
+				var cmd tea.Cmd
+				m.branches, cmd = m.branches.Update(msg)
+				if m.branches.FilterValue() != before {
+					m.branchErr = ""
+				}
+				m.resetAddItemTitle()
+				return m, cmd
+			}
+			switch {
+			case key.Matches(msg, m.keys.Back):
+				if m.branches.IsFiltered() {
+					m.branches.ResetFilter()
+					m.branchErr = ""
+					m.resetAddItemTitle()
+					return m, nil
+				}
 				m.state = stateList
 				return m, nil
-			case "n":
+			case key.Matches(msg, m.keys.Fetch):
+				return m, m.startFetch()
+			case key.Matches(msg, m.keys.NewBranch):
 				if m.branchDel != nil {
 					m.branchDel.editing = true
 					m.input.SetValue("")
+					m.validateBranchInput()
 					m.input.Focus()
 					// Ensure selection stays on the add item (index 0)
 					m.branches.Select(0)
 					m.updateAddItemTitle("")
 				}
 				return m, nil
-			case "enter":
+			case key.Matches(msg, m.keys.Select):
 				if it, ok := m.branches.SelectedItem().(item); ok {
+					if it.isAdd && m.createQuery() != "" {
+						return m, m.createFromFilter()
+					}
 					if it.isAdd {
 						if m.branchDel != nil {
 							m.branchDel.editing = true
 							m.input.SetValue("")
+							m.validateBranchInput()
 							m.input.Focus()
 							m.updateAddItemTitle("")
 						}
 						return m, nil
 					}
 					b := it.br
-					branchName := b.Name
-					path := git.DefaultWorktreeDir(branchName)
-					if err := git.CreateWorktree(branchName, path, false); err != nil {
-						return m, m.branches.NewStatusMessage(fmt.Sprintf("Error: %v", err))
+					req := addRequest{branch: b.Name}
+					if b.IsRemote {
+						// Remote-only branch: create a local tracking branch from it
+						req.fromRef = b.RemoteRef
+						req.remote = b.Remote
+					} else if up, _, ok := strings.Cut(b.Upstream, "/"); ok {
+						req.remote = up
 					}
-					m.state = stateList
-					name := filepath.Base(path)
-					return m, tea.Batch(loadWorktrees, m.list.NewStatusMessage(fmt.Sprintf("Created worktree %s", name)))
+					return m, m.promptPath(req)
 				}
 				return m, nil
 			}
@@ -490,32 +920,151 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				if branch == "" {
 					return m, nil
 				}
-				path := git.DefaultWorktreeDir(branch)
-				if err := git.CreateWorktree(branch, path, true); err != nil {
-					// Return to list and show error
-					m.state = stateList
-					return m, m.list.NewStatusMessage(fmt.Sprintf("Error: %v", err))
+				return m, m.pickBaseRef(branch)
+			}
+			var cmd tea.Cmd
+			m.input, cmd = m.input.Update(msg)
+			return m, cmd
+		case stateAddBase:
+			// Inline editing mode for the "Enter a ref or commit" synthetic item
+			if m.baseDel.editing {
+				switch k {
+				case "esc":
+					m.baseDel.editing = false
+					m.refInput.Blur()
+					m.updateBaseItemTitle("")
+					return m, nil
+				case "enter":
+					ref := strings.TrimSpace(m.refInput.Value())
+					if ref == "" {
+						return m, nil
+					}
+					req := m.pending
+					req.fromRef = ref
+					m.baseDel.editing = false
+					m.refInput.Blur()
+					m.updateBaseItemTitle("")
+					return m, m.promptPath(req)
+				}
+				var cmd tea.Cmd
+				m.refInput, cmd = m.refInput.Update(msg)
+				m.updateBaseItemTitle(m.refInput.Value())
+				return m, cmd
+			}
+			switch {
+			case key.Matches(msg, m.keys.Back):
+				// Back to the branch picker with the typed name still in the input
+				m.state = stateAddPick
+				m.branchDel.editing = true
+				if name, _ := m.branchName(); name != m.pending.branch {
+					// The name came from the filter rather than the typed title
+					m.prefix = 0
+					m.input.SetValue(m.pending.branch)
 				}
+				m.validateBranchInput()
+				m.input.Focus()
+				m.branches.Select(0)
+				m.updateAddItemTitle(m.input.Value())
+				return m, nil
+			case key.Matches(msg, m.keys.Select):
+				if it, ok := m.bases.SelectedItem().(item); ok {
+					if it.isAdd {
+						m.baseDel.editing = true
+						m.refInput.SetValue("")
+						m.refInput.Focus()
+						m.updateBaseItemTitle("")
+						return m, nil
+					}
+					req := m.pending
+					req.fromRef = it.ref
+					if r, _, ok := strings.Cut(it.ref, "/"); ok && it.remote {
+						req.remote = r
+					}
+					return m, m.promptPath(req)
+				}
+				return m, nil
+			}
+			var cmd tea.Cmd
+			m.bases, cmd = m.bases.Update(msg)
+			return m, cmd
+		case stateActions:
+			switch {
+			case key.Matches(msg, m.keys.Back):
 				m.state = stateList
-				name := filepath.Base(path)
-				return m, tea.Batch(loadWorktrees, m.list.NewStatusMessage(fmt.Sprintf("Created worktree %s", name)))
+				return m, nil
+			case key.Matches(msg, m.keys.Select):
+				if it, ok := m.actionList.SelectedItem().(item); ok {
+					return m, m.runAction(it.act, m.selected)
+				}
+				return m, nil
 			}
 			var cmd tea.Cmd
-			m.input, cmd = m.input.Update(msg)
+			m.actionList, cmd = m.actionList.Update(msg)
 			return m, cmd
-		case stateConfirmDelete:
+		case stateAddPath:
 			switch k {
 			case "esc":
-				m.state = stateList
+				m.pathInput.Blur()
+				m.state = m.pathBack
 				return m, nil
 			case "enter":
+				path := strings.TrimSpace(m.pathInput.Value())
+				if path == "" {
+					m.pathErr = "path required"
+					return m, nil
+				}
+				return m, m.createWorktree(m.pending, path)
+			}
+			var cmd tea.Cmd
+			m.pathInput, cmd = m.pathInput.Update(msg)
+			return m, cmd
+		case stateHooks:
+			if key.Matches(msg, m.keys.Back, m.keys.Select, m.keys.Quit) {
+				m.state = stateList
+				return m, nil
+			}
+			var cmd tea.Cmd
+			m.hookLog, cmd = m.hookLog.Update(msg)
+			return m, cmd
+		case stateConfirmDelete:
+			switch {
+			case key.Matches(msg, m.keys.Back):
+				m.state = stateList
+				return m, nil
+			case key.Matches(msg, m.keys.Select):
+				m.pendingBranch = ""
+				return m, m.removeWorktree(m.selected)
+			case key.Matches(msg, m.keys.DeleteBranch):
+				return m, m.removeWorktreeAndBranch(m.selected)
+			}
+		case stateConfirmForceDelete:
+			switch {
+			case key.Matches(msg, m.keys.Back):
+				m.state = stateList
+				m.pendingBranch = ""
+				return m, m.list.NewStatusMessage(fmt.Sprintf("Kept worktree %s", filepath.Base(m.selected.Path)))
+			case key.Matches(msg, m.keys.Select):
+				m.state = stateList
 				if err := git.RemoveWorktree(m.selected.Path, true); err != nil {
-					m.state = stateList
 					return m, m.list.NewStatusMessage(fmt.Sprintf("Error: %v", err))
 				}
+				return m, m.worktreeRemoved(m.selected)
+			}
+		case stateConfirmDeleteBranch:
+			switch {
+			case key.Matches(msg, m.keys.Back):
+				m.state = stateList
+				b := m.pendingBranch
+				m.pendingBranch = ""
+				return m, m.list.NewStatusMessage(fmt.Sprintf("Kept branch %s", b))
+			case key.Matches(msg, m.keys.Select):
 				m.state = stateList
-				name := filepath.Base(m.selected.Path)
-				return m, tea.Batch(loadWorktrees, m.list.NewStatusMessage(fmt.Sprintf("Removed worktree %s", name)))
+				b := m.pendingBranch
+				m.pendingBranch = ""
+				if err := git.DeleteBranch(b, true); err != nil {
+					return m, m.list.NewStatusMessage(fmt.Sprintf("Error: %v", err))
+				}
+				return m, tea.Batch(loadWorktrees, m.list.NewStatusMessage(fmt.Sprintf("Deleted branch %s", b)))
 			}
 		}
 	}
@@ -525,56 +1074,54 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 func (m model) View() string {
 	switch m.state {
 	case stateList:
+		if m.split {
+			return m.frame.Render(lipgloss.JoinHorizontal(lipgloss.Top, m.list.View(), m.previewView()))
+		}
 		return m.frame.Render(m.list.View())
 	case stateAddPick:
 		return m.frame.Render(m.branches.View())
 	case stateAddNewInput:
 		return m.frame.Render(m.input.View())
-	case stateConfirmDelete:
-		return m.frame.Render(m.confirmMsg)
+	case stateAddBase:
+		return m.frame.Render(m.bases.View())
+	case stateAddPath:
+		return m.frame.Render(m.pathView())
+	case stateActions:
+		return m.frame.Render(m.actionList.View())
+	case stateHooks:
+		return m.frame.Render(m.hooksView())
+	case stateConfirmDelete, stateConfirmForceDelete, stateConfirmDeleteBranch:
+		return m.frame.Render(lipgloss.NewStyle().Padding(1, 2).Height(m.list.Height()).Render(m.confirmMsg))
 	}
 	return ""
 }
 
-// buildEditorCmd constructs an *exec.Cmd to open the given path in the user's editor.
-// It uses $VISUAL, then $EDITOR; if neither is set, returns an error.
-func buildEditorCmd(path string) (*exec.Cmd, error) {
-	editor := os.Getenv("VISUAL")
-	if editor == "" {
-		editor = os.Getenv("EDITOR")
-	}
-	if editor == "" {
-		return nil, fmt.Errorf("$EDITOR not set")
-	}
-	// Open the directory in the editor; common CLIs support a path argument
-	cmd := exec.Command(editor, path)
-	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	return cmd, nil
-}
-
 // stripANSI removes ANSI escape sequences from s.
 // (removed) stripANSI helper no longer needed; we update the list item directly.
 
 // updateAddItemTitle updates the title of the synthetic add-new-branch item (index 0)
 func (m *model) updateAddItemTitle(val string) {
-	if m.branches.Items() == nil || len(m.branches.Items()) == 0 {
-		return
+	desc := addBranchDesc
+	if m.branchErr != "" {
+		desc = lipgloss.NewStyle().Foreground(theme.Red).Render(m.branchErr)
 	}
-	it0, ok := m.branches.Items()[0].(item)
-	if !ok || !it0.isAdd {
-		return
-	}
-	title := val
 	// Only substitute the default label when not actively editing
-	if strings.TrimSpace(title) == "" && !(m.branchDel != nil && m.branchDel.editing) {
-		title = "[+] Create new branch"
+	editing := m.branchDel != nil && m.branchDel.editing
+	if editing {
+		val = m.branchPrefix() + val
+		if m.branchErr == "" {
+			desc = m.branchInputDesc()
+		}
+	} else {
+		val = ""
+	}
+	if q := m.createQuery(); q != "" && !editing {
+		val = fmt.Sprintf("[+] Create branch '%s'", q)
+		if m.branchErr == "" {
+			desc = "Create a new branch with this name"
+		}
 	}
-	it0.title = title
-	items := m.branches.Items()
-	items[0] = it0
-	m.branches.SetItems(items)
+	setAddItem(&m.branches, val, addBranchLabel, desc, editing)
 }
 
 // resetAddItemTitle resets the synthetic add item title back to its default label
@@ -0,0 +1,183 @@
+package tui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/fredrikmwold/git-worktree-tui/internal/git"
+	"github.com/fredrikmwold/git-worktree-tui/internal/theme"
+)
+
+const (
+	// previewMinWidth is the inner width from which the worktree list shares
+	// the screen with the preview pane.
+	previewMinWidth = 120
+	previewCommits  = 10
+)
+
+// preview is what the preview pane shows for a worktree beyond its status.
+type preview struct {
+	loading bool
+	commits []git.Commit
+	changed time.Time // newest uncommitted file; zero when clean
+	err     error
+}
+
+type loadedPreviewMsg struct {
+	path    string
+	preview preview
+}
+
+// loadPreview returns a command reading the recent history and last change of the worktree at path.
+func loadPreview(path string) tea.Cmd {
+	return func() tea.Msg {
+		var p preview
+		p.commits, p.err = git.RecentCommits(path, previewCommits)
+		if p.err == nil {
+			p.changed, p.err = git.LastChanged(path)
+		}
+		return loadedPreviewMsg{path: path, preview: p}
+	}
+}
+
+// layoutPreview splits the inner width between the list and the preview pane,
+// or gives it all to the list when the terminal is too narrow.
+func (m *model) layoutPreview(innerW, innerH int) {
+	m.split = innerW >= previewMinWidth
+	listW := innerW
+	if m.split {
+		listW = innerW * 3 / 5
+	}
+	m.previewW, m.previewH = innerW-listW, innerH
+	m.list.SetSize(listW, innerH)
+	ls := m.list.Styles
+	ls.HelpStyle = ls.HelpStyle.Foreground(theme.Surface2).MaxWidth(listW)
+	m.list.Styles = ls
+}
+
+// loadSelectedPreview starts loading the preview of the selected worktree
+// unless the pane is hidden or it is cached. Loads are cached per path until
+// the worktrees are reloaded, so moving the cursor back and forth stays cheap.
+func (m *model) loadSelectedPreview() tea.Cmd {
+	if !m.split {
+		return nil
+	}
+	it, ok := m.list.SelectedItem().(item)
+	if !ok || it.isAdd || it.wt.Path == "" || it.wt.IsBare || it.wt.Prunable {
+		return nil
+	}
+	// Failed loads are shown but not cached, so selecting the worktree again retries
+	if p, ok := m.previews[it.wt.Path]; ok && p.err == nil {
+		return nil
+	}
+	m.previews[it.wt.Path] = preview{loading: true}
+	return loadPreview(it.wt.Path)
+}
+
+// previewView renders the preview pane for the selected worktree.
+func (m model) previewView() string {
+	pane := lipgloss.NewStyle().
+		Width(max(m.previewW-1, 0)).MaxWidth(m.previewW).Height(m.previewH).MaxHeight(m.previewH).
+		Border(lipgloss.NormalBorder(), false, false, false, true).BorderForeground(theme.Surface1).
+		Padding(0, 0, 0, 1)
+	w := max(m.previewW-2, 0)
+	muted := lipgloss.NewStyle().Foreground(theme.Surface2)
+	it, ok := m.list.SelectedItem().(item)
+	if !ok || it.isAdd {
+		return pane.Render(muted.Width(w).Render("Select a worktree to see its recent commits and changes."))
+	}
+	wt := it.wt
+	label := func(c lipgloss.Color, s string) string { return lipgloss.NewStyle().Foreground(c).Render(s) }
+	var b strings.Builder
+	b.WriteString(m.list.Styles.Title.Render(filepath.Base(wt.Path)) + "\n\n")
+	line := func(l string) { b.WriteString(lipgloss.NewStyle().MaxWidth(w).Render(l) + "\n") }
+	if br := wt.BranchName(); br != "" {
+		line(label(theme.Sky, "Branch:") + " " + br)
+	} else if !wt.IsBare {
+		line(label(theme.Sky, "Detached at:") + " " + git.ShortHash(wt.HEAD))
+	}
+	if wt.IsBare || wt.Prunable {
+		line(label(theme.Green, "Path:") + " " + wt.Path)
+		return pane.Render(b.String())
+	}
+	st := m.status(wt.Path)
+	if st != nil {
+		up := muted.Render("none")
+		if st.Upstream != "" {
+			up = st.Upstream
+			if st.Ahead > 0 || st.Behind > 0 {
+				up += fmt.Sprintf(" (%d ahead, %d behind)", st.Ahead, st.Behind)
+			} else {
+				up += " (up to date)"
+			}
+		}
+		line(label(theme.Blue, "Upstream:") + " " + up)
+		line(label(theme.Lavender, "Status:") + " " + statusDetail(*st))
+	}
+	p, ok := m.previews[wt.Path]
+	switch {
+	case !ok || p.loading:
+		line("\n" + muted.Render("Loading…"))
+	case p.err != nil:
+		line("\n" + lipgloss.NewStyle().Foreground(theme.Red).Width(w).Render(p.err.Error()))
+	default:
+		last := p.changed
+		if len(p.commits) > 0 && p.commits[0].When.After(last) {
+			last = p.commits[0].When
+		}
+		if !last.IsZero() {
+			line(label(theme.Peach, "Modified:") + " " + ago(last))
+		}
+		line("\n" + label(theme.Mauve, "Recent commits"))
+		if len(p.commits) == 0 {
+			line(muted.Render("No commits yet"))
+		}
+		for _, c := range p.commits {
+			line(label(theme.Yellow, c.Hash) + " " + c.Subject + " " + muted.Render(ago(c.When)))
+		}
+	}
+	return pane.Render(b.String())
+}
+
+// statusDetail spells out a worktree's uncommitted changes, e.g. "2 staged, 1 untracked".
+func statusDetail(st git.Status) string {
+	if !st.Dirty() {
+		return lipgloss.NewStyle().Foreground(theme.Green).Render("clean")
+	}
+	var parts []string
+	for _, p := range []struct {
+		n    int
+		what string
+	}{{st.Conflicted, "conflicted"}, {st.Staged, "staged"}, {st.Unstaged, "modified"}, {st.Untracked, "untracked"}} {
+		if p.n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", p.n, p.what))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// ago formats t relative to now, e.g. "3 hours ago", falling back to the date after a month.
+func ago(t time.Time) string {
+	d := time.Since(t)
+	unit := func(n int, s string) string {
+		if n == 1 {
+			return fmt.Sprintf("1 %s ago", s)
+		}
+		return fmt.Sprintf("%d %ss ago", n, s)
+	}
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return unit(int(d.Minutes()), "minute")
+	case d < 24*time.Hour:
+		return unit(int(d.Hours()), "hour")
+	case d < 30*24*time.Hour:
+		return unit(int(d.Hours()/24), "day")
+	}
+	return t.Format("2006-01-02")
+}
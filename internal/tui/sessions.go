@@ -0,0 +1,71 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fredrikmwold/git-worktree-tui/internal/git"
+	"github.com/fredrikmwold/git-worktree-tui/internal/mux"
+)
+
+// loadedSessionsMsg carries the live multiplexer sessions.
+type loadedSessionsMsg struct {
+	sessions []mux.Session
+}
+
+// loadSessions lists live tmux/zellij sessions. Listing is best effort: a
+// multiplexer that fails to answer simply contributes no sessions.
+func loadSessions() tea.Msg {
+	var sessions []mux.Session
+	for _, k := range mux.Kinds {
+		ss, err := mux.ListSessions(k)
+		if err != nil {
+			continue
+		}
+		sessions = append(sessions, ss...)
+	}
+	return loadedSessionsMsg{sessions: sessions}
+}
+
+// liveSessions returns the multiplexers running the session of the worktree at
+// path. Sessions with the same name but started elsewhere don't count.
+func (m *model) liveSessions(path string) []mux.Kind {
+	var kinds []mux.Kind
+	for _, s := range m.sessions {
+		if s.Owns(path) {
+			kinds = append(kinds, s.Kind)
+		}
+	}
+	return kinds
+}
+
+// killSessions ends the live sessions belonging to wt when killSessionOnDelete
+// is set, returning a note for the status message ("" if nothing was killed).
+func (m *model) killSessions(wt git.Worktree) string {
+	if !m.cfg.KillSessionOnDelete {
+		return ""
+	}
+	name := mux.SessionName(wt.Path)
+	var killed, failed []string
+	for _, k := range m.liveSessions(wt.Path) {
+		if err := mux.KillSession(k, name); err != nil {
+			failed = append(failed, string(k))
+			continue
+		}
+		killed = append(killed, string(k))
+	}
+	m.sessions = slices.DeleteFunc(m.sessions, func(s mux.Session) bool { return s.Owns(wt.Path) })
+	var notes []string
+	if len(killed) > 0 {
+		notes = append(notes, fmt.Sprintf("killed %s session %s", strings.Join(killed, "/"), name))
+	}
+	if len(failed) > 0 {
+		notes = append(notes, fmt.Sprintf("could not kill %s session %s", strings.Join(failed, "/"), name))
+	}
+	if len(notes) == 0 {
+		return ""
+	}
+	return "; " + strings.Join(notes, ", ")
+}